@@ -1,9 +1,12 @@
 package contabo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -23,13 +26,29 @@ type TokenResponse struct {
 	Scope            string `json:"scope"`
 }
 
-// AuthManager handles OAuth2 authentication and token management
+// AuthManager handles OAuth2 authentication and token management using the
+// password grant. It is the default TokenSource of a Client.
 type AuthManager struct {
-	config      *Config
-	httpClient  *http.Client
-	token       *TokenResponse
-	tokenExpiry time.Time
-	mu          sync.RWMutex
+	config        *Config
+	httpClient    *http.Client
+	token         *TokenResponse
+	tokenExpiry   time.Time
+	refreshExpiry time.Time
+	inflight      *tokenCall
+	invalidated   string        // Access token rejected by the API; never reused from the cache
+	timeout       time.Duration // Deadline of a token request shared by several callers
+	mu            sync.RWMutex
+}
+
+// authTimeout bounds a token request, including a fallback from the refresh
+// grant to the password grant, whatever the HTTP client's timeout
+const authTimeout = 30 * time.Second
+
+// tokenCall is a token request shared by all goroutines waiting for it
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
 }
 
 // NewAuthManager creates a new authentication manager
@@ -37,35 +56,259 @@ func NewAuthManager(config *Config, httpClient *http.Client) *AuthManager {
 	return &AuthManager{
 		config:     config,
 		httpClient: httpClient,
+		timeout:    authTimeout,
+	}
+}
+
+// GetAccessToken returns a valid access token, refreshing if necessary.
+// Concurrent callers share a single token request; each caller stops
+// waiting as soon as its own ctx is done.
+func (a *AuthManager) GetAccessToken(ctx context.Context) (string, error) {
+	token, err := a.TokenContext(ctx)
+	if err != nil {
+		return "", err
 	}
+	return token.AccessToken, nil
 }
 
-// GetAccessToken returns a valid access token, refreshing if necessary
-func (a *AuthManager) GetAccessToken() (string, error) {
+// Token implements TokenSource
+func (a *AuthManager) Token() (*Token, error) {
+	return a.TokenContext(context.Background())
+}
+
+// TokenContext implements ContextTokenSource
+func (a *AuthManager) TokenContext(ctx context.Context) (*Token, error) {
 	a.mu.RLock()
 	// Check if we have a valid token
 	if a.token != nil && time.Now().Before(a.tokenExpiry) {
-		token := a.token.AccessToken
+		token := a.currentToken()
 		a.mu.RUnlock()
 		return token, nil
 	}
 	a.mu.RUnlock()
 
-	// Token is expired or doesn't exist, acquire new token
+	return a.acquire(ctx, false)
+}
+
+// Refresh forces a token refresh
+func (a *AuthManager) Refresh(ctx context.Context) error {
+	_, err := a.acquire(ctx, true)
+	return err
+}
+
+// InvalidateToken marks accessToken as rejected by the API so that the next
+// call obtains a new token. It is a no-op if a different token is already held.
+func (a *AuthManager) InvalidateToken(accessToken string) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.token != nil && a.token.AccessToken == accessToken {
+		a.tokenExpiry = time.Time{}
+		a.invalidated = accessToken
+		a.logger().Info("contabo: access token invalidated", slog.String("username", a.config.Username))
+	}
+}
+
+// acquire joins the in-flight token request or starts a new one and waits for it
+func (a *AuthManager) acquire(ctx context.Context, force bool) (*Token, error) {
+	a.mu.Lock()
+
 	// Double-check after acquiring write lock
-	if a.token != nil && time.Now().Before(a.tokenExpiry) {
-		return a.token.AccessToken, nil
+	if !force && a.token != nil && time.Now().Before(a.tokenExpiry) {
+		token := a.currentToken()
+		a.mu.Unlock()
+		return token, nil
+	}
+
+	call := a.inflight
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		a.inflight = call
+
+		var refreshToken string
+		if a.token != nil && time.Now().Before(a.refreshExpiry) {
+			refreshToken = a.token.RefreshToken
+		}
+
+		stale := a.invalidated
+		if force && a.token != nil {
+			stale = a.token.AccessToken
+		}
+
+		// The request must outlive the caller that happened to start it, since
+		// other goroutines may be waiting on it, but has its own deadline so that
+		// a hung token endpoint cannot block them when the HTTP client has none
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.timeout)
+		go func() {
+			defer cancel()
+			a.fetch(fetchCtx, call, refreshToken, stale)
+		}()
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch performs the token request for call and publishes the result
+func (a *AuthManager) fetch(ctx context.Context, call *tokenCall, refreshToken, stale string) {
+	entry, err := a.obtainToken(ctx, refreshToken, stale)
+
+	a.mu.Lock()
+	if err == nil {
+		a.setToken(entry)
+		call.token = a.currentToken()
+	}
+	call.err = err
+	a.inflight = nil
+	a.mu.Unlock()
+
+	close(call.done)
+}
+
+// obtainToken returns a token from the persistent cache when possible and
+// otherwise authenticates and updates the cache. A cached access token equal
+// to stale is not reused.
+func (a *AuthManager) obtainToken(ctx context.Context, refreshToken, stale string) (*cachedToken, error) {
+	if cached := a.loadCachedToken(); cached != nil {
+		now := time.Now()
+		if cached.Token.AccessToken != "" && cached.Token.AccessToken != stale && now.Before(cached.TokenExpiry) {
+			a.logger().Debug("contabo: using cached access token",
+				slog.String("username", a.config.Username),
+				slog.Time("expiry", cached.TokenExpiry),
+			)
+			return cached, nil
+		}
+		if refreshToken == "" && cached.Token.RefreshToken != "" && now.Before(cached.RefreshExpiry) {
+			refreshToken = cached.Token.RefreshToken
+		}
+	}
+
+	tokenResp, err := a.authenticate(ctx, refreshToken)
+	if err != nil {
+		return nil, err
 	}
 
-	// Request new token
-	return a.authenticate()
+	entry := newCachedToken(tokenResp)
+	a.saveCachedToken(entry)
+	return entry, nil
 }
 
-// authenticate performs the OAuth2 password grant flow
-func (a *AuthManager) authenticate() (string, error) {
+// loadCachedToken reads the entry for this account from the token cache.
+// The cache is best-effort: unreadable or undecryptable entries are ignored.
+func (a *AuthManager) loadCachedToken() *cachedToken {
+	if a.config.TokenCache == nil {
+		return nil
+	}
+
+	key := tokenCacheKey(a.config)
+	data, err := a.config.TokenCache.Load(key)
+	if err != nil || data == nil {
+		return nil
+	}
+
+	entry, err := openCachedToken(a.config.ClientSecret, key, data)
+	if err != nil {
+		return nil
+	}
+	return entry
+}
+
+// saveCachedToken writes entry to the token cache, ignoring failures
+func (a *AuthManager) saveCachedToken(entry *cachedToken) {
+	if a.config.TokenCache == nil {
+		return
+	}
+
+	key := tokenCacheKey(a.config)
+	data, err := sealCachedToken(a.config.ClientSecret, key, entry)
+	if err != nil {
+		return
+	}
+	_ = a.config.TokenCache.Store(key, data)
+}
+
+// authenticate obtains a new token, preferring the refresh_token grant when
+// a refresh token is available. It falls back to the password grant only if
+// the refresh token is rejected (400/401 invalid_grant); transport errors,
+// server errors and cancellation are returned as is, so the password is not
+// sent again just because the token endpoint is briefly unreachable.
+func (a *AuthManager) authenticate(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	logger := a.logger()
+
+	if refreshToken != "" {
+		start := time.Now()
+		tokenResp, err := a.refreshGrant(ctx, refreshToken)
+		a.observeRefresh("refresh_token", start, err)
+		if err == nil {
+			logger.LogAttrs(ctx, slog.LevelInfo, "contabo: access token refreshed",
+				slog.String("grant_type", "refresh_token"),
+				slog.String("username", a.config.Username),
+				slog.Int("expires_in", tokenResp.ExpiresIn),
+			)
+			return tokenResp, nil
+		}
+		if !refreshRejected(err) {
+			logger.LogAttrs(ctx, slog.LevelError, "contabo: token refresh failed",
+				slog.String("grant_type", "refresh_token"),
+				slog.String("username", a.config.Username),
+				slog.String("error", err.Error()),
+			)
+			return nil, err
+		}
+		// The refresh token was rejected (e.g. session revoked); log in again
+		logger.LogAttrs(ctx, slog.LevelWarn, "contabo: refresh token rejected, falling back to password grant",
+			slog.String("username", a.config.Username),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	start := time.Now()
+	tokenResp, err := a.passwordGrant(ctx)
+	a.observeRefresh("password", start, err)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "contabo: authentication failed",
+			slog.String("grant_type", "password"),
+			slog.String("username", a.config.Username),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "contabo: authenticated",
+		slog.String("grant_type", "password"),
+		slog.String("username", a.config.Username),
+		slog.Int("expires_in", tokenResp.ExpiresIn),
+	)
+	return tokenResp, nil
+}
+
+// observeRefresh reports a token request to the configured metrics collector
+func (a *AuthManager) observeRefresh(grantType string, start time.Time, err error) {
+	if a.config.Metrics == nil {
+		return
+	}
+	a.config.Metrics.ObserveTokenRefresh(TokenRefreshMetric{
+		GrantType: grantType,
+		Success:   err == nil,
+		Duration:  time.Since(start),
+	})
+}
+
+// logger returns the configured logger or one that discards everything
+func (a *AuthManager) logger() *slog.Logger {
+	if a.config.Logger != nil {
+		return a.config.Logger
+	}
+	return discardLogger
+}
+
+// passwordGrant performs the OAuth2 password grant flow
+func (a *AuthManager) passwordGrant(ctx context.Context) (*TokenResponse, error) {
 	data := url.Values{}
 	data.Set("grant_type", "password")
 	data.Set("client_id", a.config.ClientID)
@@ -73,45 +316,131 @@ func (a *AuthManager) authenticate() (string, error) {
 	data.Set("username", a.config.Username)
 	data.Set("password", a.config.Password)
 
-	req, err := http.NewRequest("POST", a.config.AuthURL, strings.NewReader(data.Encode()))
+	return a.requestToken(ctx, data)
+}
+
+// refreshGrant performs the OAuth2 refresh_token grant flow
+func (a *AuthManager) refreshGrant(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", a.config.ClientID)
+	data.Set("client_secret", a.config.ClientSecret)
+	data.Set("refresh_token", refreshToken)
+
+	return a.requestToken(ctx, data)
+}
+
+// requestToken posts the given form to the token endpoint
+func (a *AuthManager) requestToken(ctx context.Context, data url.Values) (*TokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", a.config.AuthURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create auth request: %w", err)
+		return nil, fmt.Errorf("failed to create auth request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if a.config.UserAgent != "" {
+		req.Header.Set("User-Agent", a.config.UserAgent)
+	}
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("authentication request failed: %w", err)
+		return nil, fmt.Errorf("authentication request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read auth response: %w", err)
+		return nil, fmt.Errorf("failed to read auth response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%w: status %d, body: %s", ErrAuthenticationFailed, resp.StatusCode, string(body))
+		tokenErr := &tokenError{StatusCode: resp.StatusCode, Body: string(body)}
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil {
+			tokenErr.Code = oauthErr.Error
+		}
+		return nil, tokenErr
 	}
 
 	var tokenResp TokenResponse
 	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return "", fmt.Errorf("failed to parse token response: %w", err)
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
 
-	// Store token and calculate expiry (subtract 60 seconds as buffer)
-	a.token = &tokenResp
-	a.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
+	return &tokenResp, nil
+}
 
-	return tokenResp.AccessToken, nil
+// tokenError is a non-200 response of the token endpoint. It wraps
+// ErrAuthenticationFailed.
+type tokenError struct {
+	StatusCode int
+	Code       string // OAuth2 error code, e.g. "invalid_grant"
+	Body       string
 }
 
-// Refresh forces a token refresh
-func (a *AuthManager) Refresh() error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+func (e *tokenError) Error() string {
+	return fmt.Sprintf("%s: status %d, body: %s", ErrAuthenticationFailed, e.StatusCode, e.Body)
+}
 
-	_, err := a.authenticate()
-	return err
+func (e *tokenError) Unwrap() error {
+	return ErrAuthenticationFailed
+}
+
+// refreshRejected reports whether err means the refresh token itself is no
+// longer accepted, as opposed to the token endpoint being unreachable or failing
+func refreshRejected(err error) bool {
+	var tokenErr *tokenError
+	if !errors.As(err, &tokenErr) {
+		return false
+	}
+	return (tokenErr.StatusCode == http.StatusBadRequest || tokenErr.StatusCode == http.StatusUnauthorized) &&
+		tokenErr.Code == "invalid_grant"
+}
+
+// newCachedToken calculates both expiries of a token response (subtracting 60 seconds as buffer)
+func newCachedToken(tokenResp *TokenResponse) *cachedToken {
+	now := time.Now()
+	entry := &cachedToken{
+		Token:       *tokenResp,
+		TokenExpiry: now.Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second),
+	}
+
+	if tokenResp.RefreshToken != "" && tokenResp.RefreshExpiresIn > 0 {
+		entry.RefreshExpiry = now.Add(time.Duration(tokenResp.RefreshExpiresIn-60) * time.Second)
+	}
+	return entry
+}
+
+// setToken records the token and its expiries; callers hold a.mu
+func (a *AuthManager) setToken(entry *cachedToken) {
+	token := entry.Token
+	a.token = &token
+	a.tokenExpiry = entry.TokenExpiry
+	a.refreshExpiry = entry.RefreshExpiry
+}
+
+// currentToken converts the stored token response; callers hold a.mu
+func (a *AuthManager) currentToken() *Token {
+	return &Token{
+		AccessToken: a.token.AccessToken,
+		TokenType:   a.token.TokenType,
+		Expiry:      a.tokenExpiry,
+	}
+}
+
+// TokenExpiry returns when the current access token is considered expired
+func (a *AuthManager) TokenExpiry() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.tokenExpiry
+}
+
+// RefreshExpiry returns when the current refresh token is considered expired.
+// The zero time means no usable refresh token is held.
+func (a *AuthManager) RefreshExpiry() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.refreshExpiry
 }
@@ -0,0 +1,206 @@
+package contabo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthenticateRefreshFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		refresh      func(w http.ResponseWriter)
+		wantPassword bool
+		wantErr      bool
+	}{
+		{"refreshed", func(w http.ResponseWriter) { writeToken(w, "refreshed") }, false, false},
+		{"invalid_grant 400", func(w http.ResponseWriter) { writeOAuthError(w, http.StatusBadRequest, "invalid_grant") }, true, false},
+		{"invalid_grant 401", func(w http.ResponseWriter) { writeOAuthError(w, http.StatusUnauthorized, "invalid_grant") }, true, false},
+		{"invalid_client", func(w http.ResponseWriter) { writeOAuthError(w, http.StatusUnauthorized, "invalid_client") }, false, true},
+		{"server error", func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }, false, true},
+		{"connection dropped", func(http.ResponseWriter) { panic(http.ErrAbortHandler) }, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var passwordGrants atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.PostFormValue("grant_type") {
+				case "refresh_token":
+					tt.refresh(w)
+				case "password":
+					passwordGrants.Add(1)
+					writeToken(w, "password")
+				}
+			}))
+			defer server.Close()
+
+			auth := NewAuthManager(&Config{AuthURL: server.URL}, server.Client())
+			_, err := auth.authenticate(context.Background(), "refresh-token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := passwordGrants.Load() == 1; got != tt.wantPassword {
+				t.Errorf("password grant used = %v, want %v", got, tt.wantPassword)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRefreshCancelled(t *testing.T) {
+	var passwordGrants atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("grant_type") == "password" {
+			passwordGrants.Add(1)
+		}
+		writeToken(w, "token")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	auth := NewAuthManager(&Config{AuthURL: server.URL}, server.Client())
+	if _, err := auth.authenticate(ctx, "refresh-token"); !errors.Is(err, context.Canceled) {
+		t.Errorf("authenticate() error = %v, want context.Canceled", err)
+	}
+	if passwordGrants.Load() != 0 {
+		t.Errorf("password grant used after cancellation")
+	}
+}
+
+func TestAcquireSingleFlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelled int // Index of the caller whose context is cancelled while waiting
+	}{
+		{"starting caller cancels", 0},
+		{"later caller cancels", 3},
+	}
+
+	const callers = 5
+	type result struct {
+		caller int
+		token  string
+		err    error
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			received := make(chan struct{}, callers)
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				received <- struct{}{}
+				<-release
+				writeToken(w, "shared")
+			}))
+			defer server.Close()
+
+			auth := NewAuthManager(&Config{AuthURL: server.URL}, server.Client())
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			results := make(chan result, callers)
+			start := func(i int) {
+				callCtx := context.Background()
+				if i == tt.cancelled {
+					callCtx = ctx
+				}
+				go func() {
+					token, err := auth.GetAccessToken(callCtx)
+					results <- result{i, token, err}
+				}()
+			}
+
+			// The first caller starts the request; the others join it while it is blocked
+			start(0)
+			<-received
+			for i := 1; i < callers; i++ {
+				start(i)
+			}
+
+			// The cancelled caller returns while the token request is still blocked
+			cancel()
+			select {
+			case r := <-results:
+				if r.caller != tt.cancelled || !errors.Is(r.err, context.Canceled) {
+					t.Fatalf("caller %d returned %q, %v first, want caller %d with context.Canceled", r.caller, r.token, r.err, tt.cancelled)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("cancelled caller still waiting")
+			}
+
+			close(release)
+			for range callers - 1 {
+				r := <-results
+				if r.err != nil || r.token != "shared" {
+					t.Errorf("caller %d: token = %q, error = %v, want the shared token", r.caller, r.token, r.err)
+				}
+			}
+			if n := requests.Load(); n != 1 {
+				t.Errorf("token requests = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// Hang until the client gives up, which the server notices once the
+			// request body has been read
+			r.ParseForm()
+			<-r.Context().Done()
+			return
+		}
+		writeToken(w, "token")
+	}))
+	defer server.Close()
+
+	// An HTTP client without a timeout, as configured with WithTimeout(0)
+	auth := NewAuthManager(&Config{AuthURL: server.URL}, &http.Client{})
+	auth.timeout = 50 * time.Millisecond
+
+	const callers = 3
+	errs := make(chan error, callers)
+	for range callers {
+		go func() {
+			_, err := auth.GetAccessToken(context.Background())
+			errs <- err
+		}()
+	}
+	for range callers {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("GetAccessToken() error = %v, want context.DeadlineExceeded", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("GetAccessToken() blocked on a hung token endpoint")
+		}
+	}
+
+	// The timed out request no longer blocks later callers
+	if token, err := auth.GetAccessToken(context.Background()); err != nil || token != "token" {
+		t.Errorf("GetAccessToken() = %q, %v, want a new token", token, err)
+	}
+}
+
+func writeToken(w http.ResponseWriter, accessToken string) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":%q,"expires_in":300,"refresh_expires_in":1800,"refresh_token":"r","token_type":"Bearer"}`, accessToken)
+}
+
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q}`, code)
+}
@@ -4,53 +4,112 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client is the main Contabo API client
 type Client struct {
 	config      *Config
 	httpClient  *http.Client
+	tokenSource TokenSource
 	authManager *AuthManager
 	baseURL     *url.URL
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	tracer      Tracer
+	logger      *slog.Logger
+	metrics     MetricsCollector
+	middleware  []Middleware
+	handler     Handler
 }
 
-// NewClient creates a new Contabo API client
-func NewClient(config *Config) (*Client, error) {
-	if err := config.Validate(); err != nil {
+// NewClient creates a new Contabo API client. Options override the
+// corresponding Config fields without modifying config.
+func NewClient(config *Config, opts ...Option) (*Client, error) {
+	config, err := applyOptions(config, opts)
+	if err != nil {
 		return nil, err
 	}
 
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
-	if config.HTTPClient != nil {
-		if hc, ok := config.HTTPClient.(*http.Client); ok {
-			httpClient = hc
-		}
-	}
+	httpClient := config.HTTPClient
 
 	baseURL, err := url.Parse(config.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
 
-	authManager := NewAuthManager(config, httpClient)
+	// Use the configured token source, or the password grant by default
+	var authManager *AuthManager
+	tokenSource := config.TokenSource
+	if tokenSource == nil {
+		authManager = NewAuthManager(config, httpClient)
+		tokenSource = authManager
+	}
+
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = DefaultRetryPolicy()
+	}
+
+	rateLimiter := config.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = NewRateLimiter(config.RateLimit)
+	}
+
+	tracer := config.Tracer
+	if tracer == nil {
+		tracer = noopTracer{}
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = discardLogger
+	}
 
-	return &Client{
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	c := &Client{
 		config:      config,
 		httpClient:  httpClient,
+		tokenSource: tokenSource,
 		authManager: authManager,
 		baseURL:     baseURL,
-	}, nil
+		retryPolicy: retryPolicy,
+		rateLimiter: rateLimiter,
+		tracer:      tracer,
+		logger:      logger,
+		metrics:     metrics,
+	}
+	c.handler = c.buildHandler()
+
+	return c, nil
+}
+
+// TokenSource returns the token source used to authorize requests
+func (c *Client) TokenSource() TokenSource {
+	return c.tokenSource
+}
+
+// RateLimiter returns the client's rate limiter, or nil if rate limiting is disabled
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.rateLimiter
 }
 
 // NewRequest creates a new HTTP request with authentication and required headers
@@ -78,48 +137,39 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body inter
 	}
 
 	// Get access token
-	token, err := c.authManager.GetAccessToken()
+	token, err := tokenFromSource(ctx, c.tokenSource)
 	if err != nil {
 		return nil, err
 	}
 
 	// Set required headers
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("x-request-id", uuid.New().String())
+	req.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	requestID, ok := RequestIDFromContext(ctx)
+	if !ok {
+		requestID = uuid.New().String()
+	}
+	req.Header.Set("x-request-id", requestID)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
 
 	// Optional trace ID from context
-	if traceID, ok := ctx.Value("x-trace-id").(string); ok && traceID != "" {
+	if traceID, ok := TraceIDFromContext(ctx); ok {
 		req.Header.Set("x-trace-id", traceID)
 	}
 
 	return req, nil
 }
 
-// Do executes an HTTP request and handles the response
+// Do executes an HTTP request and handles the response, retrying transient
+// failures according to the client's RetryPolicy. If the API rejects the
+// access token, a new token is obtained and the request is replayed once.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.httpClient.Do(req)
+	start := time.Now()
+	resp, body, err := c.execute(req)
+	c.logCall(req, resp, body, err, time.Since(start))
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return resp, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		apiErr := NewAPIError(
-			resp.StatusCode,
-			string(body),
-			req.Header.Get("x-request-id"),
-			req.Header.Get("x-trace-id"),
-		)
-		return resp, apiErr
+		return resp, err
 	}
 
 	// Decode response if v is provided
@@ -132,90 +182,272 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	return resp, nil
 }
 
-// Get performs a GET request
-func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
-	req, err := c.NewRequest(ctx, "GET", path, nil)
+// execute sends req with retries and re-authentication and turns error
+// responses into APIErrors
+func (c *Client) execute(req *http.Request) (*http.Response, []byte, error) {
+	resp, body, err := c.doWithRetry(req)
 	if err != nil {
-		return err
+		return resp, body, err
 	}
 
-	_, err = c.Do(req, v)
-	return err
+	if isInvalidTokenResponse(resp, body) && canRewind(req) {
+		c.logger.LogAttrs(req.Context(), slog.LevelInfo, "contabo: access token rejected, re-authenticating",
+			slog.String("request_id", req.Header.Get("x-request-id")),
+			slog.Int("status", resp.StatusCode),
+		)
+
+		retryReq, authErr := c.reauthorize(req)
+		if errors.Is(authErr, ErrInvalidToken) {
+			return resp, body, fmt.Errorf("%w: %w", ErrInvalidToken, c.newAPIError(req, resp, body))
+		}
+		if authErr != nil {
+			return resp, body, authErr
+		}
+
+		resp, body, err = c.doWithRetry(retryReq)
+		if err != nil {
+			return resp, body, err
+		}
+
+		if isInvalidTokenResponse(resp, body) {
+			return resp, body, fmt.Errorf("%w: %w", ErrInvalidToken, c.newAPIError(req, resp, body))
+		}
+	}
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return resp, body, c.newAPIError(req, resp, body)
+	}
+
+	return resp, body, nil
 }
 
-// Post performs a POST request
-func (c *Client) Post(ctx context.Context, path string, body, v interface{}) error {
-	req, err := c.NewRequest(ctx, "POST", path, body)
+// logCall logs a completed API call; headers and bodies are only logged at
+// debug level and with credentials redacted
+func (c *Client) logCall(req *http.Request, resp *http.Response, body []byte, err error, duration time.Duration) {
+	ctx := req.Context()
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.RequestURI()),
+		slog.Duration("duration", duration),
+		slog.String("request_id", req.Header.Get("x-request-id")),
+	}
+	if traceID := req.Header.Get("x-trace-id"); traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", traceID))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
 	if err != nil {
-		return err
+		attrs = append(attrs, slog.String("error", err.Error()))
 	}
 
-	_, err = c.Do(req, v)
-	return err
+	c.logger.LogAttrs(ctx, level, "contabo: API call", attrs...)
+
+	if c.logger.Enabled(ctx, slog.LevelDebug) {
+		debugAttrs := []slog.Attr{
+			slog.String("request_id", req.Header.Get("x-request-id")),
+			slog.Any("request_headers", redactHeader(req.Header)),
+		}
+		if req.GetBody != nil {
+			if rc, err := req.GetBody(); err == nil {
+				reqBody, _ := io.ReadAll(rc)
+				rc.Close()
+				debugAttrs = append(debugAttrs, slog.String("request_body", redactJSON(reqBody)))
+			}
+		}
+		debugAttrs = append(debugAttrs, slog.String("response_body", redactJSON(body)))
+
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "contabo: API call bodies", debugAttrs...)
+	}
 }
 
-// Put performs a PUT request
-func (c *Client) Put(ctx context.Context, path string, body, v interface{}) error {
-	req, err := c.NewRequest(ctx, "PUT", path, body)
-	if err != nil {
-		return err
+// doWithRetry sends req, retrying transient failures according to the RetryPolicy
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, []byte, error) {
+	policy := c.retryPolicy
+
+	var (
+		resp *http.Response
+		body []byte
+		err  error
+	)
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return resp, nil, err
+			}
+		}
+
+		if err = c.rateLimiter.Wait(req.Context(), req.Method); err != nil {
+			return resp, nil, err
+		}
+
+		resp, body, err = c.roundTrip(attemptReq)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) || !canRewind(req) {
+			break
+		}
+
+		delay := policy.backoff(attempt, resp)
+		c.logRetry(req, attempt, delay, resp, err)
+
+		if sleepErr := sleepContext(req.Context(), delay); sleepErr != nil {
+			if err == nil {
+				err = sleepErr
+			}
+			break
+		}
 	}
 
-	_, err = c.Do(req, v)
-	return err
+	return resp, body, err
 }
 
-// Patch performs a PATCH request
-func (c *Client) Patch(ctx context.Context, path string, body, v interface{}) error {
-	req, err := c.NewRequest(ctx, "PATCH", path, body)
+// logRetry logs that a failed attempt is going to be retried
+func (c *Client) logRetry(req *http.Request, attempt int, delay time.Duration, resp *http.Response, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.RequestURI()),
+		slog.String("request_id", req.Header.Get("x-request-id")),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
 	if err != nil {
-		return err
+		attrs = append(attrs, slog.String("error", err.Error()))
 	}
 
-	_, err = c.Do(req, v)
-	return err
+	c.logger.LogAttrs(req.Context(), slog.LevelWarn, "contabo: retrying API request", attrs...)
 }
 
-// Delete performs a DELETE request
-func (c *Client) Delete(ctx context.Context, path string) error {
-	req, err := c.NewRequest(ctx, "DELETE", path, nil)
+// reauthorize invalidates the token used by req and returns a copy of req
+// carrying a freshly obtained token
+func (c *Client) reauthorize(req *http.Request) (*http.Request, error) {
+	_, oldToken, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+	invalidateToken(c.tokenSource, oldToken)
+
+	token, err := tokenFromSource(req.Context(), c.tokenSource)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if token.AccessToken == oldToken {
+		// The source cannot provide another token (e.g. a static token)
+		return nil, ErrInvalidToken
 	}
 
-	_, err = c.Do(req, nil)
-	return err
+	retryReq, err := rewindRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	retryReq.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	return retryReq, nil
 }
 
-// BuildQueryString builds a query string from ListOptions and additional parameters
-func BuildQueryString(opts *ListOptions, params map[string]string) string {
-	values := url.Values{}
+// isInvalidTokenResponse reports whether the API rejected the access token,
+// either with 401 or with Keycloak's invalid_token error
+func isInvalidTokenResponse(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusBadRequest, http.StatusForbidden:
+		return strings.Contains(resp.Header.Get("WWW-Authenticate"), "invalid_token") ||
+			bytes.Contains(body, []byte(`"invalid_token"`))
+	}
+	return false
+}
 
-	if opts != nil {
-		if opts.Page > 0 {
-			values.Set("page", fmt.Sprintf("%d", opts.Page))
-		}
-		if opts.Size > 0 {
-			values.Set("size", fmt.Sprintf("%d", opts.Size))
-		}
-		if len(opts.OrderBy) > 0 {
-			for _, order := range opts.OrderBy {
-				values.Add("orderBy", order)
-			}
-		}
+// newAPIError builds the APIError for a failed response to req
+func (c *Client) newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	return NewAPIError(
+		resp.StatusCode,
+		string(body),
+		req.Header.Get("x-request-id"),
+		req.Header.Get("x-trace-id"),
+	)
+}
+
+// roundTrip performs a single HTTP round trip and reads the full response body
+func (c *Client) roundTrip(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer resp.Body.Close()
 
-	for k, v := range params {
-		if v != "" {
-			values.Set(k, v)
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if len(values) == 0 {
-		return ""
+	return resp, body, nil
+}
+
+// canRewind reports whether the request body can be replayed for another attempt
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindRequest clones req with a fresh body, keeping all headers (including
+// x-request-id) so that retried attempts can be correlated
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		clone.Body = body
 	}
+	return clone, nil
+}
+
+// Get performs a GET request
+func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
+	_, err := c.call(ctx, "GET", path, nil, v)
+	return err
+}
+
+// Post performs a POST request
+func (c *Client) Post(ctx context.Context, path string, body, v interface{}) error {
+	_, err := c.call(ctx, "POST", path, body, v)
+	return err
+}
+
+// Put performs a PUT request
+func (c *Client) Put(ctx context.Context, path string, body, v interface{}) error {
+	_, err := c.call(ctx, "PUT", path, body, v)
+	return err
+}
 
-	return "?" + values.Encode()
+// Patch performs a PATCH request
+func (c *Client) Patch(ctx context.Context, path string, body, v interface{}) error {
+	_, err := c.call(ctx, "PATCH", path, body, v)
+	return err
+}
+
+// Delete performs a DELETE request
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.call(ctx, "DELETE", path, nil, nil)
+	return err
+}
+
+// BuildQueryString builds a query string from ListOptions and additional
+// parameters. Values are escaped and keys sorted, so the result is stable.
+func BuildQueryString(opts *ListOptions, params map[string]string) string {
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
+	}
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
 
 // AddQueryParams adds query parameters to a path
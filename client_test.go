@@ -0,0 +1,229 @@
+package contabo_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/faultinject"
+	"github.com/mithucste30/contabo-api-golang/secret"
+)
+
+// call is a request seen by callLog
+type call struct {
+	Method        string
+	Path          string
+	Authorization string
+	RequestID     string
+	Body          string
+	Status        int
+}
+
+// callLog records every request sent through it, including attempts that
+// are retried or replayed, before passing it to Base
+type callLog struct {
+	Base http.RoundTripper
+
+	mu    sync.Mutex
+	calls []call
+}
+
+func (l *callLog) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := call{
+		Method:        req.Method,
+		Path:          req.URL.Path,
+		Authorization: req.Header.Get("Authorization"),
+		RequestID:     req.Header.Get("x-request-id"),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.Body = string(body)
+		req.Body = io.NopCloser(strings.NewReader(c.Body))
+	}
+
+	base := l.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if resp != nil {
+		c.Status = resp.StatusCode
+	}
+
+	l.mu.Lock()
+	l.calls = append(l.calls, c)
+	l.mu.Unlock()
+	return resp, err
+}
+
+// api returns the recorded API calls, leaving out token requests
+func (l *callLog) api() []call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var calls []call
+	for _, c := range l.calls {
+		if strings.HasPrefix(c.Path, "/v1/") {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// tokenRequests returns the number of recorded token requests
+func (l *callLog) tokenRequests() int {
+	api := len(l.api())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.calls) - api
+}
+
+func TestReauthenticateOnExpiredToken(t *testing.T) {
+	log := &callLog{}
+	sdk, server := contabotest.NewSDK(t, contabo.WithTransport(log))
+	instance := server.AddInstance(compute.Instance{DisplayName: "web"})
+
+	if _, err := sdk.Compute.GetInstance(context.Background(), instance.InstanceID); err != nil {
+		t.Fatalf("first GetInstance() error = %v", err)
+	}
+	server.ExpireTokens()
+	got, err := sdk.Compute.GetInstance(context.Background(), instance.InstanceID)
+	if err != nil {
+		t.Fatalf("GetInstance() after ExpireTokens error = %v", err)
+	}
+	if got.DisplayName != "web" {
+		t.Errorf("DisplayName = %q, want web", got.DisplayName)
+	}
+
+	calls := log.api()
+	if len(calls) != 3 {
+		t.Fatalf("API calls = %+v, want the first call, the rejected call and one replay", calls)
+	}
+	rejected, replay := calls[1], calls[2]
+	if rejected.Status != http.StatusUnauthorized || replay.Status != http.StatusOK {
+		t.Errorf("statuses = %d, %d, want 401 then 200", rejected.Status, replay.Status)
+	}
+	if replay.Authorization == rejected.Authorization {
+		t.Error("replay used the rejected access token")
+	}
+	if replay.RequestID != rejected.RequestID {
+		t.Errorf("replay x-request-id = %q, want %q", replay.RequestID, rejected.RequestID)
+	}
+	if n := log.tokenRequests(); n != 2 {
+		t.Errorf("token requests = %d, want 2 (initial and re-authentication)", n)
+	}
+}
+
+func TestReauthenticateStaticToken(t *testing.T) {
+	log := &callLog{}
+	server := contabotest.NewServer()
+	t.Cleanup(server.Close)
+
+	config := server.Config()
+	config.TokenSource = contabo.StaticTokenSource("revoked")
+	sdk, err := contabo.NewSDK(config, contabo.WithTransport(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sdk.Compute.ListInstances(context.Background(), nil)
+	if !errors.Is(err, contabo.ErrInvalidToken) {
+		t.Fatalf("error = %v, want ErrInvalidToken", err)
+	}
+	var apiErr *contabo.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("error = %v, want it to wrap the 401 APIError", err)
+	}
+	if calls := log.api(); len(calls) != 1 {
+		t.Errorf("API calls = %d, want 1: a static token cannot be replaced", len(calls))
+	}
+	if n := log.tokenRequests(); n != 0 {
+		t.Errorf("token requests = %d, want 0", n)
+	}
+}
+
+func TestReauthenticateReplaysOnce(t *testing.T) {
+	tests := []struct {
+		name       string
+		times      int // Requests answered with an expired token error
+		wantErr    error
+		wantCalls  int
+		wantTokens int
+	}{
+		{"replay succeeds", 1, nil, 2, 2},
+		{"replay rejected again", 2, contabo.ErrInvalidToken, 2, 2},
+		{"every request rejected", 10, contabo.ErrInvalidToken, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faults := faultinject.New(nil, faultinject.Rule{Path: "/v1/**", Fault: faultinject.ExpiredToken(), Times: tt.times})
+			log := &callLog{Base: faults}
+			sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(log))
+
+			_, err := sdk.Compute.ListInstances(context.Background(), nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if calls := log.api(); len(calls) != tt.wantCalls {
+				t.Errorf("API calls = %d, want %d", len(calls), tt.wantCalls)
+			}
+			if n := log.tokenRequests(); n != tt.wantTokens {
+				t.Errorf("token requests = %d, want %d", n, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestReauthenticateReplaysBody(t *testing.T) {
+	log := &callLog{}
+	sdk, server := contabotest.NewSDK(t, contabo.WithTransport(log))
+
+	// Authenticate first so that the POST is sent with a token that then expires
+	if _, err := sdk.Secret.ListSecrets(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	server.ExpireTokens()
+
+	created, err := sdk.Secret.CreateSecret(context.Background(), &secret.CreateSecretRequest{
+		Name:  "deploy",
+		Type:  secret.TypePassword,
+		Value: "s3cr3t-Passw0rd!",
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	if created.Name != "deploy" {
+		t.Errorf("created secret %q, want deploy", created.Name)
+	}
+
+	var posts []call
+	for _, c := range log.api() {
+		if c.Method == http.MethodPost {
+			posts = append(posts, c)
+		}
+	}
+	if len(posts) != 2 {
+		t.Fatalf("POST calls = %d, want the rejected call and one replay", len(posts))
+	}
+	if posts[0].Body == "" || posts[1].Body != posts[0].Body {
+		t.Errorf("replayed body = %q, want %q", posts[1].Body, posts[0].Body)
+	}
+
+	secrets, err := sdk.Secret.ListSecrets(context.Background(), nil)
+	if err != nil || len(secrets.Data) != 1 {
+		t.Errorf("ListSecrets() = %v, %v, want exactly one secret", secrets, err)
+	}
+}
@@ -0,0 +1,94 @@
+package compute
+
+// The types below are strings, so values the SDK does not know yet, e.g. a
+// newly opened region, are decoded and preserved unchanged; IsValid reports
+// whether a value is one of the known constants.
+
+// Region identifies a Contabo region
+type Region string
+
+// Known regions
+const (
+	RegionEU        Region = "EU"
+	RegionUSCentral Region = "US-central"
+	RegionUSEast    Region = "US-east"
+	RegionUSWest    Region = "US-west"
+	RegionSIN       Region = "SIN"
+	RegionUK        Region = "UK"
+	RegionAUS       Region = "AUS"
+	RegionJPN       Region = "JPN"
+	RegionIND       Region = "IND"
+)
+
+// IsValid reports whether r is a known region
+func (r Region) IsValid() bool {
+	switch r {
+	case RegionEU, RegionUSCentral, RegionUSEast, RegionUSWest, RegionSIN,
+		RegionUK, RegionAUS, RegionJPN, RegionIND:
+		return true
+	}
+	return false
+}
+
+// InstanceStatus is the state of an instance
+type InstanceStatus string
+
+// Known instance states
+const (
+	InstanceStatusProvisioning         InstanceStatus = "provisioning"
+	InstanceStatusInstalling           InstanceStatus = "installing"
+	InstanceStatusRunning              InstanceStatus = "running"
+	InstanceStatusStopped              InstanceStatus = "stopped"
+	InstanceStatusRescue               InstanceStatus = "rescue"
+	InstanceStatusResetPassword        InstanceStatus = "reset_password"
+	InstanceStatusUninstalled          InstanceStatus = "uninstalled"
+	InstanceStatusError                InstanceStatus = "error"
+	InstanceStatusManualProvisioning   InstanceStatus = "manual_provisioning"
+	InstanceStatusProductNotAvailable  InstanceStatus = "product_not_available"
+	InstanceStatusVerificationRequired InstanceStatus = "verification_required"
+	InstanceStatusPendingPayment       InstanceStatus = "pending_payment"
+	InstanceStatusOther                InstanceStatus = "other"
+	InstanceStatusUnknown              InstanceStatus = "unknown"
+)
+
+// IsValid reports whether s is a known instance state
+func (s InstanceStatus) IsValid() bool {
+	switch s {
+	case InstanceStatusProvisioning, InstanceStatusInstalling, InstanceStatusRunning,
+		InstanceStatusStopped, InstanceStatusRescue, InstanceStatusResetPassword,
+		InstanceStatusUninstalled, InstanceStatusError, InstanceStatusManualProvisioning,
+		InstanceStatusProductNotAvailable, InstanceStatusVerificationRequired,
+		InstanceStatusPendingPayment, InstanceStatusOther, InstanceStatusUnknown:
+		return true
+	}
+	return false
+}
+
+// OSType is the operating system family of an image
+type OSType string
+
+// Known operating system families
+const (
+	OSTypeLinux   OSType = "Linux"
+	OSTypeWindows OSType = "Windows"
+)
+
+// IsValid reports whether t is a known operating system family
+func (t OSType) IsValid() bool {
+	return t == OSTypeLinux || t == OSTypeWindows
+}
+
+// ImageStatus is the state of a custom image upload
+type ImageStatus string
+
+// Known image states
+const (
+	ImageStatusDownloading ImageStatus = "downloading"
+	ImageStatusDownloaded  ImageStatus = "downloaded"
+	ImageStatusError       ImageStatus = "error"
+)
+
+// IsValid reports whether s is a known image state
+func (s ImageStatus) IsValid() bool {
+	return s == ImageStatusDownloading || s == ImageStatusDownloaded || s == ImageStatusError
+}
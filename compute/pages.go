@@ -0,0 +1,114 @@
+package compute
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllInstances returns an iterator over the instances of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllInstances(ctx context.Context, opts *ListInstancesOptions) iter.Seq2[Instance, error] {
+	return paginate.All(ctx, instanceFields.StartPage(opts), s.instancePages(opts), instanceKey)
+}
+
+// ListAllInstances retrieves the instances of all pages, fetching up to
+// opts.Concurrency pages at once. On error the instances read so far are
+// returned with it.
+func (s *Service) ListAllInstances(ctx context.Context, opts *ListInstancesOptions) ([]Instance, error) {
+	return paginate.List(ctx, instanceFields.StartPage(opts), instanceFields.Concurrency(opts), s.instancePages(opts), instanceKey)
+}
+
+// instancePages returns a function fetching a page of instances
+func (s *Service) instancePages(opts *ListInstancesOptions) paginate.Fetch[Instance] {
+	return func(ctx context.Context, page int) (*paginate.Page[Instance], error) {
+		resp, err := s.ListInstances(ctx, instanceFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Instance]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// instanceKey identifies an instance for de-duplication
+func instanceKey(i Instance) int64 {
+	return i.InstanceID
+}
+
+// AllSnapshots returns an iterator over the snapshots of an instance of all pages
+func (s *Service) AllSnapshots(ctx context.Context, instanceID int64, opts *ListOptions) iter.Seq2[Snapshot, error] {
+	return paginate.All(ctx, listFields.StartPage(opts), s.snapshotPages(instanceID, opts), snapshotKey)
+}
+
+// ListAllSnapshots retrieves the snapshots of an instance of all pages
+func (s *Service) ListAllSnapshots(ctx context.Context, instanceID int64, opts *ListOptions) ([]Snapshot, error) {
+	return paginate.List(ctx, listFields.StartPage(opts), listFields.Concurrency(opts), s.snapshotPages(instanceID, opts), snapshotKey)
+}
+
+// snapshotPages returns a function fetching a page of snapshots of an instance
+func (s *Service) snapshotPages(instanceID int64, opts *ListOptions) paginate.Fetch[Snapshot] {
+	return func(ctx context.Context, page int) (*paginate.Page[Snapshot], error) {
+		resp, err := s.ListSnapshots(ctx, instanceID, listFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Snapshot]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// snapshotKey identifies a snapshot for de-duplication
+func snapshotKey(s Snapshot) string {
+	return s.SnapshotID
+}
+
+// AllImages returns an iterator over the images of all pages
+func (s *Service) AllImages(ctx context.Context, opts *ListImagesOptions) iter.Seq2[Image, error] {
+	return paginate.All(ctx, imageFields.StartPage(opts), s.imagePages(opts), imageKey)
+}
+
+// ListAllImages retrieves the images of all pages
+func (s *Service) ListAllImages(ctx context.Context, opts *ListImagesOptions) ([]Image, error) {
+	return paginate.List(ctx, imageFields.StartPage(opts), imageFields.Concurrency(opts), s.imagePages(opts), imageKey)
+}
+
+// imagePages returns a function fetching a page of images
+func (s *Service) imagePages(opts *ListImagesOptions) paginate.Fetch[Image] {
+	return func(ctx context.Context, page int) (*paginate.Page[Image], error) {
+		resp, err := s.ListImages(ctx, imageFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Image]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// imageKey identifies an image for de-duplication
+func imageKey(i Image) string {
+	return i.ImageID
+}
+
+// Pagination fields of the list options, for internal/paginate
+var (
+	listFields     paginate.Fields[ListOptions]          = func(o *ListOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+	instanceFields paginate.Fields[ListInstancesOptions] = func(o *ListInstancesOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+	imageFields    paginate.Fields[ListImagesOptions]    = func(o *ListImagesOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+)
@@ -3,6 +3,11 @@ package compute
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +24,59 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListInstancesOptions filters the instances returned by ListInstances.
+// Empty fields are not sent.
+type ListInstancesOptions struct {
+	ListOptions
+
+	Name         string
+	DisplayName  string
+	Region       Region
+	DataCenter   string
+	InstanceIDs  []int64
+	Status       InstanceStatus
+	ProductTypes []string // e.g. "ssd", "hdd", "nvme", "vds"
+}
+
+// params returns the filter query parameters
+func (o *ListInstancesOptions) params() map[string]string {
+	ids := make([]string, len(o.InstanceIDs))
+	for i, id := range o.InstanceIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return map[string]string{
+		"name":         o.Name,
+		"displayName":  o.DisplayName,
+		"region":       string(o.Region),
+		"dataCenter":   o.DataCenter,
+		"instanceIds":  strings.Join(ids, ","),
+		"status":       string(o.Status),
+		"productTypes": strings.Join(o.ProductTypes, ","),
+	}
+}
+
+// ListImagesOptions filters the images returned by ListImages.
+// Empty fields are not sent.
+type ListImagesOptions struct {
+	ListOptions
+
+	Name          string
+	StandardImage *bool // Only standard images if true, only custom images if false
+}
+
+// params returns the filter query parameters
+func (o *ListImagesOptions) params() map[string]string {
+	params := map[string]string{"name": o.Name}
+	if o.StandardImage != nil {
+		params["standardImage"] = strconv.FormatBool(*o.StandardImage)
+	}
+	return params
 }
 
 // Service handles compute-related API operations
@@ -34,10 +92,10 @@ func NewService(client Client) *Service {
 // Instances
 
 // ListInstances retrieves a list of compute instances
-func (s *Service) ListInstances(ctx context.Context, opts *ListOptions) (*InstancesResponse, error) {
+func (s *Service) ListInstances(ctx context.Context, opts *ListInstancesOptions) (*InstancesResponse, error) {
 	path := "/v1/compute/instances"
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp InstancesResponse
@@ -50,7 +108,10 @@ func (s *Service) ListInstances(ctx context.Context, opts *ListOptions) (*Instan
 
 // GetInstance retrieves a specific instance by ID
 func (s *Service) GetInstance(ctx context.Context, instanceID int64) (*Instance, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d", instanceID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Instance `json:"data"`
@@ -60,7 +121,7 @@ func (s *Service) GetInstance(ctx context.Context, instanceID int64) (*Instance,
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("instance not found")
+		return nil, fmt.Errorf("instance %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -84,7 +145,10 @@ func (s *Service) CreateInstance(ctx context.Context, req *CreateInstanceRequest
 
 // UpdateInstance updates an instance (PATCH)
 func (s *Service) UpdateInstance(ctx context.Context, instanceID int64, req *PatchInstanceRequest) (*Instance, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d", instanceID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Instance `json:"data"`
@@ -102,7 +166,10 @@ func (s *Service) UpdateInstance(ctx context.Context, instanceID int64, req *Pat
 
 // ReinstallInstance reinstalls an instance with a new image
 func (s *Service) ReinstallInstance(ctx context.Context, instanceID int64, req *ReinstallInstanceRequest) (*Instance, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d", instanceID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Instance `json:"data"`
@@ -120,13 +187,19 @@ func (s *Service) ReinstallInstance(ctx context.Context, instanceID int64, req *
 
 // CancelInstance cancels an instance
 func (s *Service) CancelInstance(ctx context.Context, instanceID int64) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/cancel", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/cancel", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // UpgradeInstance upgrades an instance to a different product
 func (s *Service) UpgradeInstance(ctx context.Context, instanceID int64, req *UpgradeInstanceRequest) (*Instance, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/upgrade", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/upgrade", instanceID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Instance `json:"data"`
@@ -146,37 +219,55 @@ func (s *Service) UpgradeInstance(ctx context.Context, instanceID int64, req *Up
 
 // StartInstance starts a stopped instance
 func (s *Service) StartInstance(ctx context.Context, instanceID int64) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/start", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/start", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // StopInstance stops a running instance
 func (s *Service) StopInstance(ctx context.Context, instanceID int64) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/stop", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/stop", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // RestartInstance restarts an instance
 func (s *Service) RestartInstance(ctx context.Context, instanceID int64) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/restart", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/restart", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // ShutdownInstance gracefully shuts down an instance
 func (s *Service) ShutdownInstance(ctx context.Context, instanceID int64) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/shutdown", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/shutdown", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // RescueInstance puts an instance into rescue mode
 func (s *Service) RescueInstance(ctx context.Context, instanceID int64, req *RescueInstanceRequest) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/rescue", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/rescue", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, req, nil)
 }
 
 // ResetPassword resets the root password of an instance
 func (s *Service) ResetPassword(ctx context.Context, instanceID int64, req *ResetPasswordRequest) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/actions/resetPassword", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/actions/resetPassword", instanceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, req, nil)
 }
 
@@ -184,7 +275,10 @@ func (s *Service) ResetPassword(ctx context.Context, instanceID int64, req *Rese
 
 // ListSnapshots retrieves snapshots for an instance
 func (s *Service) ListSnapshots(ctx context.Context, instanceID int64, opts *ListOptions) (*SnapshotsResponse, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots", instanceID)
+	if err != nil {
+		return nil, err
+	}
 	if opts != nil {
 		path += buildQueryString(opts, nil)
 	}
@@ -199,7 +293,10 @@ func (s *Service) ListSnapshots(ctx context.Context, instanceID int64, opts *Lis
 
 // GetSnapshot retrieves a specific snapshot
 func (s *Service) GetSnapshot(ctx context.Context, instanceID int64, snapshotID string) (*Snapshot, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Snapshot `json:"data"`
@@ -209,7 +306,7 @@ func (s *Service) GetSnapshot(ctx context.Context, instanceID int64, snapshotID
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("snapshot not found")
+		return nil, fmt.Errorf("snapshot %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -217,7 +314,10 @@ func (s *Service) GetSnapshot(ctx context.Context, instanceID int64, snapshotID
 
 // CreateSnapshot creates a new snapshot of an instance
 func (s *Service) CreateSnapshot(ctx context.Context, instanceID int64, req *CreateSnapshotRequest) (*Snapshot, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots", instanceID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots", instanceID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp CreateSnapshotResponse
 	if err := s.client.Post(ctx, path, req, &resp); err != nil {
@@ -233,7 +333,10 @@ func (s *Service) CreateSnapshot(ctx context.Context, instanceID int64, req *Cre
 
 // UpdateSnapshot updates a snapshot
 func (s *Service) UpdateSnapshot(ctx context.Context, instanceID int64, snapshotID string, req *PatchSnapshotRequest) (*Snapshot, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Snapshot `json:"data"`
@@ -251,13 +354,19 @@ func (s *Service) UpdateSnapshot(ctx context.Context, instanceID int64, snapshot
 
 // DeleteSnapshot deletes a snapshot
 func (s *Service) DeleteSnapshot(ctx context.Context, instanceID int64, snapshotID string) error {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots/%s", instanceID, snapshotID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // RollbackSnapshot rolls back an instance to a snapshot
 func (s *Service) RollbackSnapshot(ctx context.Context, instanceID int64, snapshotID string) (*Instance, error) {
-	path := fmt.Sprintf("/v1/compute/instances/%d/snapshots/%s/rollback", instanceID, snapshotID)
+	path, err := urlbuild.Path("/v1/compute/instances/%d/snapshots/%s/rollback", instanceID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp RollbackSnapshotResponse
 	if err := s.client.Post(ctx, path, nil, &resp); err != nil {
@@ -274,20 +383,10 @@ func (s *Service) RollbackSnapshot(ctx context.Context, instanceID int64, snapsh
 // Images
 
 // ListImages retrieves a list of images
-func (s *Service) ListImages(ctx context.Context, opts *ListOptions, standardImage *bool) (*ImagesResponse, error) {
+func (s *Service) ListImages(ctx context.Context, opts *ListImagesOptions) (*ImagesResponse, error) {
 	path := "/v1/compute/images"
-
-	params := make(map[string]string)
-	if standardImage != nil {
-		if *standardImage {
-			params["standardImage"] = "true"
-		} else {
-			params["standardImage"] = "false"
-		}
-	}
-
-	if opts != nil || len(params) > 0 {
-		path += buildQueryString(opts, params)
+	if opts != nil {
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp ImagesResponse
@@ -300,7 +399,10 @@ func (s *Service) ListImages(ctx context.Context, opts *ListOptions, standardIma
 
 // GetImage retrieves a specific image by ID
 func (s *Service) GetImage(ctx context.Context, imageID string) (*Image, error) {
-	path := fmt.Sprintf("/v1/compute/images/%s", imageID)
+	path, err := urlbuild.Path("/v1/compute/images/%s", imageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Image `json:"data"`
@@ -310,7 +412,7 @@ func (s *Service) GetImage(ctx context.Context, imageID string) (*Image, error)
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("image not found")
+		return nil, fmt.Errorf("image %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -334,7 +436,10 @@ func (s *Service) CreateImage(ctx context.Context, req *CreateImageRequest) (*Im
 
 // UpdateImage updates a custom image
 func (s *Service) UpdateImage(ctx context.Context, imageID string, req *PatchImageRequest) (*Image, error) {
-	path := fmt.Sprintf("/v1/compute/images/%s", imageID)
+	path, err := urlbuild.Path("/v1/compute/images/%s", imageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Image `json:"data"`
@@ -352,47 +457,17 @@ func (s *Service) UpdateImage(ctx context.Context, imageID string, req *PatchIma
 
 // DeleteImage deletes a custom image
 func (s *Service) DeleteImage(ctx context.Context, imageID string) error {
-	path := fmt.Sprintf("/v1/compute/images/%s", imageID)
+	path, err := urlbuild.Path("/v1/compute/images/%s", imageID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
-	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
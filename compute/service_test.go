@@ -0,0 +1,144 @@
+package compute_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListInstancesQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *compute.ListInstancesOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"empty options", &compute.ListInstancesOptions{}, ""},
+		{"list options", &compute.ListInstancesOptions{ListOptions: compute.ListOptions{Page: 2, Size: 50, OrderBy: []string{"name:asc"}}}, "orderBy=name%3Aasc&page=2&size=50"},
+		{"names", &compute.ListInstancesOptions{Name: "vmi1", DisplayName: "web & db"}, "displayName=web+%26+db&name=vmi1"},
+		{"typed region and status", &compute.ListInstancesOptions{Region: compute.RegionUSCentral, Status: compute.InstanceStatusRunning}, "region=US-central&status=running"},
+		{"data center", &compute.ListInstancesOptions{DataCenter: "European Union 2"}, "dataCenter=European+Union+2"},
+		{"one instance ID", &compute.ListInstancesOptions{InstanceIDs: []int64{100}}, "instanceIds=100"},
+		{"instance IDs joined", &compute.ListInstancesOptions{InstanceIDs: []int64{100, 2002, 30003}}, "instanceIds=100%2C2002%2C30003"},
+		{"empty instance IDs omitted", &compute.ListInstancesOptions{InstanceIDs: []int64{}}, ""},
+		{"product types joined", &compute.ListInstancesOptions{ProductTypes: []string{"ssd", "nvme"}}, "productTypes=ssd%2Cnvme"},
+		{
+			"all filters",
+			&compute.ListInstancesOptions{
+				ListOptions:  compute.ListOptions{Size: 10},
+				Name:         "vmi1",
+				DisplayName:  "web",
+				Region:       compute.RegionEU,
+				DataCenter:   "European Union 1",
+				InstanceIDs:  []int64{1, 2},
+				Status:       compute.InstanceStatusStopped,
+				ProductTypes: []string{"hdd"},
+			},
+			"dataCenter=European+Union+1&displayName=web&instanceIds=1%2C2&name=vmi1&productTypes=hdd&region=EU&size=10&status=stopped",
+		},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.Compute.ListInstances(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListInstances() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListImagesQuery(t *testing.T) {
+	standard, custom := true, false
+
+	tests := []struct {
+		name string
+		opts *compute.ListImagesOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"standard image unset", &compute.ListImagesOptions{}, ""},
+		{"standard images", &compute.ListImagesOptions{StandardImage: &standard}, "standardImage=true"},
+		{"custom images", &compute.ListImagesOptions{StandardImage: &custom}, "standardImage=false"},
+		{"name and page", &compute.ListImagesOptions{ListOptions: compute.ListOptions{Page: 3}, Name: "ubuntu 22.04"}, "name=ubuntu+22.04&page=3"},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.Compute.ListImages(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListImages() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListInstancesFilters(t *testing.T) {
+	sdk, server := contabotest.NewSDK(t)
+	web := server.AddInstance(compute.Instance{DisplayName: "web", Region: compute.RegionEU})
+	db := server.AddInstance(compute.Instance{DisplayName: "db", Region: compute.RegionUSEast})
+	server.AddInstance(compute.Instance{DisplayName: "cache", Region: compute.RegionEU})
+
+	tests := []struct {
+		name string
+		opts *compute.ListInstancesOptions
+		want []int64
+	}{
+		{"region", &compute.ListInstancesOptions{Region: compute.RegionUSEast}, []int64{db.InstanceID}},
+		{"display name", &compute.ListInstancesOptions{DisplayName: "web"}, []int64{web.InstanceID}},
+		{"instance IDs", &compute.ListInstancesOptions{InstanceIDs: []int64{web.InstanceID, db.InstanceID}}, []int64{web.InstanceID, db.InstanceID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := sdk.Compute.ListInstances(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("ListInstances() error = %v", err)
+			}
+			var got []int64
+			for _, instance := range resp.Data {
+				got = append(got, instance.InstanceID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("instances = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
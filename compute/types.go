@@ -4,27 +4,27 @@ import "time"
 
 // Instance represents a compute instance (VPS/VDS)
 type Instance struct {
-	TenantID      string    `json:"tenantId"`
-	CustomerID    string    `json:"customerId"`
-	InstanceID    int64     `json:"instanceId"`
-	Name          string    `json:"name"`
-	DisplayName   string    `json:"displayName"`
-	Status        string    `json:"status"`
-	ImageID       string    `json:"imageId"`
-	ImageName     string    `json:"imageName"`
-	ProductID     string    `json:"productId"`
-	Region        string    `json:"region"`
-	DataCenter    string    `json:"dataCenter"`
-	CreatedDate   time.Time `json:"createdDate"`
-	CancelDate    string    `json:"cancelDate,omitempty"`
-	IPConfig      IPConfig  `json:"ipConfig"`
-	MACAddress    string    `json:"macAddress"`
-	RAMMemoryMB   float64   `json:"ramMb"`
-	CPUCores      int       `json:"cpuCores"`
-	DiskMB        float64   `json:"diskMb"`
-	OSType        string    `json:"osType"`
-	SSHKeys       []int64   `json:"sshKeys,omitempty"`
-	DefaultUser   string    `json:"defaultUser,omitempty"`
+	TenantID    string         `json:"tenantId"`
+	CustomerID  string         `json:"customerId"`
+	InstanceID  int64          `json:"instanceId"`
+	Name        string         `json:"name"`
+	DisplayName string         `json:"displayName"`
+	Status      InstanceStatus `json:"status"`
+	ImageID     string         `json:"imageId"`
+	ImageName   string         `json:"imageName"`
+	ProductID   string         `json:"productId"`
+	Region      Region         `json:"region"`
+	DataCenter  string         `json:"dataCenter"`
+	CreatedDate time.Time      `json:"createdDate"`
+	CancelDate  string         `json:"cancelDate,omitempty"`
+	IPConfig    IPConfig       `json:"ipConfig"`
+	MACAddress  string         `json:"macAddress"`
+	RAMMemoryMB float64        `json:"ramMb"`
+	CPUCores    int            `json:"cpuCores"`
+	DiskMB      float64        `json:"diskMb"`
+	OSType      OSType         `json:"osType"`
+	SSHKeys     []int64        `json:"sshKeys,omitempty"`
+	DefaultUser string         `json:"defaultUser,omitempty"`
 }
 
 // IPConfig represents the IP configuration of an instance
@@ -67,18 +67,18 @@ type InstancesResponse struct {
 
 // CreateInstanceRequest represents the request body for creating an instance
 type CreateInstanceRequest struct {
-	ImageID        string  `json:"imageId"`
-	ProductID      string  `json:"productId"`
-	Region         string  `json:"region"`
-	SSHKeys        []int64 `json:"sshKeys,omitempty"`
-	RootPassword   int64   `json:"rootPassword,omitempty"`
-	UserData       string  `json:"userData,omitempty"`
-	License        string  `json:"license,omitempty"`
-	Period         int64   `json:"period"`
-	DisplayName    string  `json:"displayName,omitempty"`
-	DefaultUser    string  `json:"defaultUser,omitempty"`
-	AddOns         *AddOns `json:"addOns,omitempty"`
-	ApplicationID  string  `json:"applicationId,omitempty"`
+	ImageID       string  `json:"imageId"`
+	ProductID     string  `json:"productId"`
+	Region        Region  `json:"region"`
+	SSHKeys       []int64 `json:"sshKeys,omitempty"`
+	RootPassword  int64   `json:"rootPassword,omitempty"`
+	UserData      string  `json:"userData,omitempty"`
+	License       string  `json:"license,omitempty"`
+	Period        int64   `json:"period"`
+	DisplayName   string  `json:"displayName,omitempty"`
+	DefaultUser   string  `json:"defaultUser,omitempty"`
+	AddOns        *AddOns `json:"addOns,omitempty"`
+	ApplicationID string  `json:"applicationId,omitempty"`
 }
 
 // AddOns represents additional services for an instance
@@ -181,22 +181,22 @@ type RollbackSnapshotResponse struct {
 
 // Image represents a compute image
 type Image struct {
-	ImageID      string   `json:"imageId"`
-	TenantID     string   `json:"tenantId"`
-	CustomerID   string   `json:"customerId"`
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	URL          string   `json:"url"`
-	SizeMB       float64  `json:"sizeMb"`
-	UploadedSizeMB float64 `json:"uploadedSizeMb"`
-	OSType       string   `json:"osType"`
-	Version      string   `json:"version"`
-	Format       string   `json:"format"`
-	Status       string   `json:"status"`
-	ErrorMessage string   `json:"errorMessage,omitempty"`
-	StandardImage bool    `json:"standardImage"`
-	CreatedDate  time.Time `json:"createdDate"`
-	LastModifiedDate time.Time `json:"lastModifiedDate"`
+	ImageID          string      `json:"imageId"`
+	TenantID         string      `json:"tenantId"`
+	CustomerID       string      `json:"customerId"`
+	Name             string      `json:"name"`
+	Description      string      `json:"description"`
+	URL              string      `json:"url"`
+	SizeMB           float64     `json:"sizeMb"`
+	UploadedSizeMB   float64     `json:"uploadedSizeMb"`
+	OSType           OSType      `json:"osType"`
+	Version          string      `json:"version"`
+	Format           string      `json:"format"`
+	Status           ImageStatus `json:"status"`
+	ErrorMessage     string      `json:"errorMessage,omitempty"`
+	StandardImage    bool        `json:"standardImage"`
+	CreatedDate      time.Time   `json:"createdDate"`
+	LastModifiedDate time.Time   `json:"lastModifiedDate"`
 }
 
 // ImagesResponse represents the response for listing images
@@ -222,7 +222,7 @@ type CreateImageRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	URL         string `json:"url"`
-	OSType      string `json:"osType"`
+	OSType      OSType `json:"osType"`
 	Version     string `json:"version"`
 }
 
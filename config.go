@@ -1,5 +1,13 @@
 package contabo
 
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
 // Config holds the configuration for the Contabo API client
 type Config struct {
 	// OAuth2 credentials
@@ -12,8 +20,42 @@ type Config struct {
 	AuthURL string // OAuth2 token endpoint
 	BaseURL string // API base URL
 
-	// Optional custom HTTP client
-	HTTPClient interface{} // Will be *http.Client
+	// Optional token source replacing the password grant (ClientID, ClientSecret,
+	// Username and Password are then not required)
+	TokenSource TokenSource
+
+	// Optional persistent token cache shared between processes, e.g. a FileTokenCache.
+	// Entries are encrypted with a key derived from ClientSecret.
+	TokenCache TokenCache
+
+	// Optional tracer creating a span per API call (defaults to a no-op tracer)
+	Tracer Tracer
+
+	// Optional structured logger for API calls, retries and authentication.
+	// Credentials are redacted; bodies are only logged at debug level.
+	Logger *slog.Logger
+
+	// Optional collector for API call and token refresh metrics, e.g. PrometheusMetrics
+	Metrics MetricsCollector
+
+	// Optional custom HTTP client (defaults to one with DefaultTimeout)
+	HTTPClient *http.Client
+
+	// Optional User-Agent header (defaults to DefaultUserAgent)
+	UserAgent string
+
+	// Optional retry policy for transient failures (defaults to DefaultRetryPolicy)
+	RetryPolicy *RetryPolicy
+
+	// Optional client-side rate limit applied to every API request
+	RateLimit *RateLimit
+
+	// Optional pre-built limiter, e.g. shared between several clients (takes precedence over RateLimit)
+	RateLimiter *RateLimiter
+
+	// Optional dry-run plan: mutating calls (POST, PUT, PATCH, DELETE) are
+	// recorded in it and answered with a synthetic success instead of being sent
+	DryRun *Plan
 }
 
 // NewConfig creates a new Config with default values
@@ -30,6 +72,15 @@ func NewConfig(clientID, clientSecret, username, password string) *Config {
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
+	if err := validateURL(c.BaseURL); err != nil {
+		return fmt.Errorf("%w: base URL: %w", ErrInvalidConfig, err)
+	}
+	if c.TokenSource != nil {
+		return nil
+	}
+	if err := validateURL(c.AuthURL); err != nil {
+		return fmt.Errorf("%w: auth URL: %w", ErrInvalidConfig, err)
+	}
 	if c.ClientID == "" {
 		return ErrMissingClientID
 	}
@@ -44,3 +95,33 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+// validateURL checks that s is an absolute http or https URL
+func validateURL(s string) error {
+	if s == "" {
+		return errors.New("URL is required")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL %q must use http or https", s)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL %q has no host", s)
+	}
+	return nil
+}
+
+// LogValue implements slog.LogValuer so that credentials are never logged
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("clientId", c.ClientID),
+		slog.String("clientSecret", "[REDACTED]"),
+		slog.String("username", c.Username),
+		slog.String("password", "[REDACTED]"),
+		slog.String("authUrl", c.AuthURL),
+		slog.String("baseUrl", c.BaseURL),
+	)
+}
@@ -0,0 +1,527 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/dns"
+)
+
+// Standard images available on every new server
+var standardImages = []compute.Image{
+	{ImageID: "afecbb85-e2fc-46f0-9684-b46b1faf00bb", Name: "ubuntu-22.04", Description: "Ubuntu 22.04", OSType: "Linux", Version: "22.04", Format: "qcow2", Status: compute.ImageStatusDownloaded, StandardImage: true, SizeMB: 2252},
+	{ImageID: "d64d5c6c-9dda-4e38-8174-0ee282474d8a", Name: "debian-12", Description: "Debian 12", OSType: "Linux", Version: "12", Format: "qcow2", Status: compute.ImageStatusDownloaded, StandardImage: true, SizeMB: 1830},
+	{ImageID: "ef2a1d1e-4c89-4a5c-a0b6-8ce3f5b0b5b0", Name: "windows-server-2022-de", Description: "Windows Server 2022 Datacenter", OSType: "Windows", Version: "2022", Format: "qcow2", Status: compute.ImageStatusDownloaded, StandardImage: true, SizeMB: 10240},
+}
+
+// AddInstance stores an instance, filling in the ID and defaults of unset fields
+func (s *Server) AddInstance(instance compute.Instance) compute.Instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillInstance(&instance)
+	s.storeInstance(instance)
+	return instance
+}
+
+// AddSnapshot stores a snapshot of an instance, filling in the ID and dates if unset
+func (s *Server) AddSnapshot(snapshot compute.Snapshot) compute.Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snapshot.SnapshotID == "" {
+		snapshot.SnapshotID = fmt.Sprintf("snap%d", s.newID())
+	}
+	fillOwner(&snapshot.TenantID, &snapshot.CustomerID)
+	if snapshot.CreatedDate.IsZero() {
+		snapshot.CreatedDate = now()
+	}
+	s.snapshots.add(snapshot)
+	return snapshot
+}
+
+// AddImage stores a custom image, filling in the ID and dates if unset
+func (s *Server) AddImage(image compute.Image) compute.Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillImage(&image)
+	s.images.add(image)
+	return image
+}
+
+// fillInstance sets the ID and defaults of unset instance fields
+func (s *Server) fillInstance(i *compute.Instance) {
+	if i.InstanceID == 0 {
+		i.InstanceID = s.newID()
+	}
+	fillOwner(&i.TenantID, &i.CustomerID)
+	if i.Name == "" {
+		i.Name = fmt.Sprintf("vmi%d", i.InstanceID)
+	}
+	if i.Status == "" {
+		i.Status = compute.InstanceStatusRunning
+	}
+	if i.ProductID == "" {
+		i.ProductID = "V45"
+	}
+	if i.Region == "" {
+		i.Region = "EU"
+	}
+	if i.DataCenter == "" {
+		i.DataCenter = "European Union 1"
+	}
+	if i.ImageID == "" {
+		i.ImageID = standardImages[0].ImageID
+	}
+	if image, ok := s.images.get(i.ImageID); ok {
+		if i.ImageName == "" {
+			i.ImageName = image.Name
+		}
+		if i.OSType == "" {
+			i.OSType = image.OSType
+		}
+	}
+	if i.CreatedDate.IsZero() {
+		i.CreatedDate = now()
+	}
+	if i.IPConfig.V4.IP == "" {
+		n := i.InstanceID % 65000
+		i.IPConfig.V4 = compute.IPConfigV4{
+			IP:      fmt.Sprintf("10.%d.%d.%d", n/250%250, n%250, 10),
+			Gateway: fmt.Sprintf("10.%d.%d.1", n/250%250, n%250),
+			Netmask: "24",
+		}
+		i.IPConfig.V6 = compute.IPConfigV6{
+			IP:      fmt.Sprintf("2001:db8:%x::1", n),
+			Gateway: "fe80::1",
+			Netmask: "64",
+		}
+	}
+	if i.MACAddress == "" {
+		i.MACAddress = fmt.Sprintf("00:50:56:%02x:%02x:%02x", byte(i.InstanceID>>16), byte(i.InstanceID>>8), byte(i.InstanceID))
+	}
+	if i.CPUCores == 0 {
+		i.CPUCores, i.RAMMemoryMB, i.DiskMB = 4, 8192, 204800
+	}
+}
+
+// storeInstance adds an instance and the reverse DNS entries of its addresses
+func (s *Server) storeInstance(i compute.Instance) {
+	s.instances.add(i)
+	for _, ip := range []string{i.IPConfig.V4.IP, i.IPConfig.V6.IP} {
+		if _, ok := s.ptrRecords.get(ip); !ok && ip != "" {
+			s.ptrRecords.add(dns.PTRRecord{
+				IPAddress:  ip,
+				TenantID:   i.TenantID,
+				CustomerID: i.CustomerID,
+				PTR:        fmt.Sprintf("%s.contaboserver.net", i.Name),
+			})
+		}
+	}
+}
+
+// fillImage sets the ID and defaults of unset image fields
+func (s *Server) fillImage(i *compute.Image) {
+	if i.ImageID == "" {
+		i.ImageID = newUUID()
+	}
+	fillOwner(&i.TenantID, &i.CustomerID)
+	if i.Status == "" {
+		i.Status = compute.ImageStatusDownloaded
+	}
+	if i.Format == "" {
+		i.Format = "qcow2"
+	}
+	if i.CreatedDate.IsZero() {
+		i.CreatedDate = now()
+	}
+	if i.LastModifiedDate.IsZero() {
+		i.LastModifiedDate = i.CreatedDate
+	}
+}
+
+// fillOwner sets the default tenant and customer
+func fillOwner(tenantID, customerID *string) {
+	if *tenantID == "" {
+		*tenantID = TenantID
+	}
+	if *customerID == "" {
+		*customerID = CustomerID
+	}
+}
+
+// registerCompute adds the instance, snapshot and image routes
+func (s *Server) registerCompute(mux *http.ServeMux) {
+	for _, image := range standardImages {
+		s.fillImage(&image)
+		s.images.add(image)
+	}
+
+	s.handle(mux, "GET /v1/compute/instances", s.listInstances)
+	s.handle(mux, "POST /v1/compute/instances", s.createInstance)
+	s.handle(mux, "GET /v1/compute/instances/{instanceId}", s.getInstance)
+	s.handle(mux, "PATCH /v1/compute/instances/{instanceId}", s.updateInstance)
+	s.handle(mux, "PUT /v1/compute/instances/{instanceId}", s.reinstallInstance)
+	s.handle(mux, "POST /v1/compute/instances/{instanceId}/cancel", s.cancelInstance)
+	s.handle(mux, "POST /v1/compute/instances/{instanceId}/upgrade", s.upgradeInstance)
+	s.handle(mux, "POST /v1/compute/instances/{instanceId}/actions/{action}", s.instanceAction)
+
+	s.handle(mux, "GET /v1/compute/instances/{instanceId}/snapshots", s.listSnapshots)
+	s.handle(mux, "POST /v1/compute/instances/{instanceId}/snapshots", s.createSnapshot)
+	s.handle(mux, "GET /v1/compute/instances/{instanceId}/snapshots/{snapshotId}", s.getSnapshot)
+	s.handle(mux, "PATCH /v1/compute/instances/{instanceId}/snapshots/{snapshotId}", s.updateSnapshot)
+	s.handle(mux, "DELETE /v1/compute/instances/{instanceId}/snapshots/{snapshotId}", s.deleteSnapshot)
+	s.handle(mux, "POST /v1/compute/instances/{instanceId}/snapshots/{snapshotId}/rollback", s.rollbackSnapshot)
+
+	s.handle(mux, "GET /v1/compute/images", s.listImages)
+	s.handle(mux, "POST /v1/compute/images", s.createImage)
+	s.handle(mux, "GET /v1/compute/images/{imageId}", s.getImage)
+	s.handle(mux, "PATCH /v1/compute/images/{imageId}", s.updateImage)
+	s.handle(mux, "DELETE /v1/compute/images/{imageId}", s.deleteImage)
+}
+
+// instance returns the instance of the request path, applying pending status changes
+func (s *Server) instance(r *http.Request) (*compute.Instance, error) {
+	id := r.PathValue("instanceId")
+	instance, ok := s.instances.get(id)
+	if !ok {
+		return nil, notFound("Instance", "instanceId", id)
+	}
+	advance(s, "instance", id, &instance.Status)
+	return instance, nil
+}
+
+func (s *Server) listInstances(r *http.Request) (int, interface{}, error) {
+	for i := range s.instances.items {
+		instance := &s.instances.items[i]
+		advance(s, "instance", fmt.Sprint(instance.InstanceID), &instance.Status)
+	}
+	page, err := paginate(r, s.instances.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createInstance(r *http.Request) (int, interface{}, error) {
+	var req compute.CreateInstanceRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.ImageID != "" {
+		if _, ok := s.images.get(req.ImageID); !ok {
+			return 0, nil, badRequest("image %s does not exist", req.ImageID)
+		}
+	}
+
+	instance := compute.Instance{
+		DisplayName: req.DisplayName,
+		ImageID:     req.ImageID,
+		ProductID:   req.ProductID,
+		Region:      req.Region,
+		SSHKeys:     req.SSHKeys,
+		DefaultUser: req.DefaultUser,
+		Status:      compute.InstanceStatusProvisioning,
+	}
+	s.fillInstance(&instance)
+	s.storeInstance(instance)
+	transition(s, "instance", fmt.Sprint(instance.InstanceID), compute.InstanceStatusRunning)
+
+	return http.StatusCreated, single(r, instance), nil
+}
+
+func (s *Server) getInstance(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, instance), nil
+}
+
+func (s *Server) updateInstance(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req compute.PatchInstanceRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.DisplayName != nil {
+		instance.DisplayName = *req.DisplayName
+	}
+	return http.StatusOK, single(r, instance), nil
+}
+
+func (s *Server) reinstallInstance(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req compute.ReinstallInstanceRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	image, ok := s.images.get(req.ImageID)
+	if !ok {
+		return 0, nil, badRequest("image %s does not exist", req.ImageID)
+	}
+
+	instance.ImageID, instance.ImageName, instance.OSType = image.ImageID, image.Name, image.OSType
+	instance.SSHKeys, instance.DefaultUser = req.SSHKeys, req.DefaultUser
+	instance.Status = compute.InstanceStatusInstalling
+	transition(s, "instance", fmt.Sprint(instance.InstanceID), compute.InstanceStatusRunning)
+
+	return http.StatusOK, single(r, instance), nil
+}
+
+func (s *Server) cancelInstance(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if instance.CancelDate == "" {
+		instance.CancelDate = time.Now().AddDate(0, 1, 0).Format(time.DateOnly)
+	}
+	return http.StatusOK, single(r, instance), nil
+}
+
+func (s *Server) upgradeInstance(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req compute.UpgradeInstanceRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.ProductID != "" {
+		instance.ProductID = req.ProductID
+	}
+	return http.StatusOK, single(r, instance), nil
+}
+
+func (s *Server) instanceAction(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	action := r.PathValue("action")
+	if instance.Status == compute.InstanceStatusProvisioning || instance.Status == compute.InstanceStatusInstalling {
+		return 0, nil, &apiError{http.StatusConflict, fmt.Sprintf("instance %d is %s", instance.InstanceID, instance.Status)}
+	}
+	switch action {
+	case "start", "restart":
+		instance.Status = compute.InstanceStatusRunning
+	case "stop", "shutdown":
+		instance.Status = compute.InstanceStatusStopped
+	case "rescue":
+		instance.Status = compute.InstanceStatusRescue
+	case "resetPassword":
+	default:
+		return 0, nil, &apiError{http.StatusNotFound, fmt.Sprintf("Cannot POST %s", r.URL.Path)}
+	}
+
+	return http.StatusCreated, single(r, map[string]interface{}{
+		"tenantId":   instance.TenantID,
+		"customerId": instance.CustomerID,
+		"instanceId": instance.InstanceID,
+		"action":     action,
+	}), nil
+}
+
+// snapshot returns the snapshot of the request path
+func (s *Server) snapshot(r *http.Request) (*compute.Snapshot, error) {
+	if _, err := s.instance(r); err != nil {
+		return nil, err
+	}
+	id := r.PathValue("snapshotId")
+	snapshot, ok := s.snapshots.get(r.PathValue("instanceId") + "/" + id)
+	if !ok {
+		return nil, notFound("Snapshot", "snapshotId", id)
+	}
+	return snapshot, nil
+}
+
+func (s *Server) listSnapshots(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var snapshots []compute.Snapshot
+	for _, snapshot := range s.snapshots.items {
+		if snapshot.InstanceID == instance.InstanceID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	page, err := paginate(r, snapshots)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createSnapshot(r *http.Request) (int, interface{}, error) {
+	instance, err := s.instance(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req compute.CreateSnapshotRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" {
+		return 0, nil, badRequest("name must not be empty")
+	}
+
+	snapshot := compute.Snapshot{
+		SnapshotID:  fmt.Sprintf("snap%d", s.newID()),
+		InstanceID:  instance.InstanceID,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedDate: now(),
+	}
+	fillOwner(&snapshot.TenantID, &snapshot.CustomerID)
+	s.snapshots.add(snapshot)
+
+	return http.StatusCreated, single(r, snapshot), nil
+}
+
+func (s *Server) getSnapshot(r *http.Request) (int, interface{}, error) {
+	snapshot, err := s.snapshot(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, snapshot), nil
+}
+
+func (s *Server) updateSnapshot(r *http.Request) (int, interface{}, error) {
+	snapshot, err := s.snapshot(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req compute.PatchSnapshotRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		snapshot.Name = *req.Name
+	}
+	if req.Description != nil {
+		snapshot.Description = *req.Description
+	}
+	return http.StatusOK, single(r, snapshot), nil
+}
+
+func (s *Server) deleteSnapshot(r *http.Request) (int, interface{}, error) {
+	snapshot, err := s.snapshot(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	s.snapshots.remove(s.snapshots.key(snapshot))
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) rollbackSnapshot(r *http.Request) (int, interface{}, error) {
+	if _, err := s.snapshot(r); err != nil {
+		return 0, nil, err
+	}
+	instance, _ := s.instance(r)
+	instance.Status = compute.InstanceStatusInstalling
+	transition(s, "instance", fmt.Sprint(instance.InstanceID), compute.InstanceStatusRunning)
+	return http.StatusOK, single(r, instance), nil
+}
+
+// image returns the image of the request path, applying pending status changes
+func (s *Server) image(r *http.Request) (*compute.Image, error) {
+	id := r.PathValue("imageId")
+	image, ok := s.images.get(id)
+	if !ok {
+		return nil, notFound("Image", "imageId", id)
+	}
+	advance(s, "image", id, &image.Status)
+	return image, nil
+}
+
+func (s *Server) listImages(r *http.Request) (int, interface{}, error) {
+	for i := range s.images.items {
+		image := &s.images.items[i]
+		advance(s, "image", image.ImageID, &image.Status)
+	}
+	page, err := paginate(r, s.images.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createImage(r *http.Request) (int, interface{}, error) {
+	var req compute.CreateImageRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" || req.URL == "" {
+		return 0, nil, badRequest("name and url must not be empty")
+	}
+
+	image := compute.Image{
+		Name:        req.Name,
+		Description: req.Description,
+		URL:         req.URL,
+		OSType:      req.OSType,
+		Version:     req.Version,
+		Status:      compute.ImageStatusDownloading,
+	}
+	s.fillImage(&image)
+	s.images.add(image)
+	transition(s, "image", image.ImageID, compute.ImageStatusDownloaded)
+
+	return http.StatusCreated, single(r, image), nil
+}
+
+func (s *Server) getImage(r *http.Request) (int, interface{}, error) {
+	image, err := s.image(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, image), nil
+}
+
+func (s *Server) updateImage(r *http.Request) (int, interface{}, error) {
+	image, err := s.image(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if image.StandardImage {
+		return 0, nil, &apiError{http.StatusForbidden, "standard images cannot be modified"}
+	}
+	var req compute.PatchImageRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		image.Name = *req.Name
+	}
+	if req.Description != nil {
+		image.Description = *req.Description
+	}
+	image.LastModifiedDate = now()
+	return http.StatusOK, single(r, image), nil
+}
+
+func (s *Server) deleteImage(r *http.Request) (int, interface{}, error) {
+	image, err := s.image(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if image.StandardImage {
+		return 0, nil, &apiError{http.StatusForbidden, "standard images cannot be deleted"}
+	}
+	s.images.remove(image.ImageID)
+	return http.StatusNoContent, nil, nil
+}
+
+// parseID parses a numeric path value
+func parseID(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, badRequest("%s must be a number", name)
+	}
+	return id, nil
+}
@@ -0,0 +1,281 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/dns"
+)
+
+// Name servers added to every new zone
+var nameServers = []string{"ns1.contabo.net", "ns2.contabo.net", "ns3.contabo.net"}
+
+// AddZone stores a DNS zone with the default NS records, filling in the ID and dates if unset
+func (s *Server) AddZone(zone dns.Zone) dns.Zone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.storeZone(&zone)
+	return zone
+}
+
+// AddRecord stores a record of an existing zone, filling in the ID if unset
+func (s *Server) AddRecord(zoneName string, record dns.Record) (dns.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, ok := s.records[zoneName]
+	if !ok {
+		return dns.Record{}, notFound("Zone", "zoneName", zoneName)
+	}
+	s.fillRecord(&record)
+	records.add(record)
+	return record, nil
+}
+
+// AddPTRRecord stores a reverse DNS entry
+func (s *Server) AddPTRRecord(ptr dns.PTRRecord) dns.PTRRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fillOwner(&ptr.TenantID, &ptr.CustomerID)
+	s.ptrRecords.remove(ptr.IPAddress)
+	s.ptrRecords.add(ptr)
+	return ptr
+}
+
+// storeZone fills in a zone and adds it with its NS records
+func (s *Server) storeZone(zone *dns.Zone) {
+	if zone.ZoneID == "" {
+		zone.ZoneID = newUUID()
+	}
+	fillOwner(&zone.TenantID, &zone.CustomerID)
+	if zone.CreatedDate.IsZero() {
+		zone.CreatedDate = now()
+	}
+	if zone.UpdatedDate.IsZero() {
+		zone.UpdatedDate = zone.CreatedDate
+	}
+	s.zones.add(*zone)
+
+	records := &store[dns.Record]{key: func(r *dns.Record) string { return r.RecordID }}
+	for _, ns := range nameServers {
+		record := dns.Record{Name: "@", Type: "NS", Content: ns, TTL: 86400}
+		s.fillRecord(&record)
+		records.add(record)
+	}
+	s.records[zone.Name] = records
+}
+
+// fillRecord sets the ID and owner of a record
+func (s *Server) fillRecord(r *dns.Record) {
+	if r.RecordID == "" {
+		r.RecordID = fmt.Sprint(s.newID())
+	}
+	fillOwner(&r.TenantID, &r.CustomerID)
+	if r.TTL == 0 {
+		r.TTL = 86400
+	}
+}
+
+// registerDNS adds the zone, record and PTR routes
+func (s *Server) registerDNS(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/dns/zones", s.listZones)
+	s.handle(mux, "POST /v1/dns/zones", s.createZone)
+	s.handle(mux, "GET /v1/dns/zones/{zoneName}", s.getZone)
+	s.handle(mux, "DELETE /v1/dns/zones/{zoneName}", s.deleteZone)
+	s.handle(mux, "GET /v1/dns/zones/{zoneName}/records", s.listRecords)
+	s.handle(mux, "POST /v1/dns/zones/{zoneName}/records", s.createRecord)
+	s.handle(mux, "GET /v1/dns/zones/{zoneName}/records/{recordId}", s.getRecord)
+	s.handle(mux, "PATCH /v1/dns/zones/{zoneName}/records/{recordId}", s.updateRecord)
+	s.handle(mux, "DELETE /v1/dns/zones/{zoneName}/records/{recordId}", s.deleteRecord)
+	s.handle(mux, "GET /v1/dns/ptrs", s.listPTRRecords)
+	s.handle(mux, "GET /v1/dns/ptrs/{ipAddress}", s.getPTRRecord)
+	s.handle(mux, "PATCH /v1/dns/ptrs/{ipAddress}", s.updatePTRRecord)
+}
+
+// zone returns the zone of the request path
+func (s *Server) zone(r *http.Request) (*dns.Zone, error) {
+	name := r.PathValue("zoneName")
+	zone, ok := s.zones.get(name)
+	if !ok {
+		return nil, notFound("Zone", "zoneName", name)
+	}
+	return zone, nil
+}
+
+// record returns the record of the request path
+func (s *Server) record(r *http.Request) (*store[dns.Record], *dns.Record, error) {
+	zone, err := s.zone(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	records := s.records[zone.Name]
+	id := r.PathValue("recordId")
+	record, ok := records.get(id)
+	if !ok {
+		return nil, nil, notFound("Record", "recordId", id)
+	}
+	return records, record, nil
+}
+
+func (s *Server) listZones(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.zones.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createZone(r *http.Request) (int, interface{}, error) {
+	var req dns.CreateZoneRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	name := strings.TrimSuffix(strings.ToLower(req.Name), ".")
+	if name == "" || !strings.Contains(name, ".") {
+		return 0, nil, badRequest("name must be a valid domain name")
+	}
+	if _, ok := s.zones.get(name); ok {
+		return 0, nil, &apiError{http.StatusConflict, fmt.Sprintf("zone %s already exists", name)}
+	}
+
+	zone := dns.Zone{Name: name}
+	s.storeZone(&zone)
+	return http.StatusCreated, single(r, zone), nil
+}
+
+func (s *Server) getZone(r *http.Request) (int, interface{}, error) {
+	zone, err := s.zone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, zone), nil
+}
+
+func (s *Server) deleteZone(r *http.Request) (int, interface{}, error) {
+	zone, err := s.zone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	delete(s.records, zone.Name)
+	s.zones.remove(zone.Name)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) listRecords(r *http.Request) (int, interface{}, error) {
+	zone, err := s.zone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	page, err := paginate(r, s.records[zone.Name].items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createRecord(r *http.Request) (int, interface{}, error) {
+	zone, err := s.zone(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req dns.CreateRecordRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Type == "" || req.Content == "" {
+		return 0, nil, badRequest("type and content are required")
+	}
+
+	record := dns.Record{
+		Name:     req.Name,
+		Type:     dns.RecordType(strings.ToUpper(string(req.Type))),
+		Content:  req.Content,
+		TTL:      req.TTL,
+		Priority: req.Priority,
+	}
+	s.fillRecord(&record)
+	s.records[zone.Name].add(record)
+	zone.UpdatedDate = now()
+
+	return http.StatusCreated, single(r, record), nil
+}
+
+func (s *Server) getRecord(r *http.Request) (int, interface{}, error) {
+	_, record, err := s.record(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, record), nil
+}
+
+func (s *Server) updateRecord(r *http.Request) (int, interface{}, error) {
+	_, record, err := s.record(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req dns.PatchRecordRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		record.Name = *req.Name
+	}
+	if req.Type != nil {
+		record.Type = dns.RecordType(strings.ToUpper(string(*req.Type)))
+	}
+	if req.Content != nil {
+		record.Content = *req.Content
+	}
+	if req.TTL != nil {
+		record.TTL = *req.TTL
+	}
+	if req.Priority != nil {
+		record.Priority = req.Priority
+	}
+	return http.StatusOK, single(r, record), nil
+}
+
+func (s *Server) deleteRecord(r *http.Request) (int, interface{}, error) {
+	records, record, err := s.record(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	records.remove(record.RecordID)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) listPTRRecords(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.ptrRecords.items)
+	return http.StatusOK, page, err
+}
+
+// ptrRecord returns the reverse DNS entry of the request path
+func (s *Server) ptrRecord(r *http.Request) (*dns.PTRRecord, error) {
+	ip := r.PathValue("ipAddress")
+	ptr, ok := s.ptrRecords.get(ip)
+	if !ok {
+		return nil, notFound("PTR", "ipAddress", ip)
+	}
+	return ptr, nil
+}
+
+func (s *Server) getPTRRecord(r *http.Request) (int, interface{}, error) {
+	ptr, err := s.ptrRecord(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, ptr), nil
+}
+
+func (s *Server) updatePTRRecord(r *http.Request) (int, interface{}, error) {
+	ptr, err := s.ptrRecord(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req dns.PatchPTRRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.PTR == "" {
+		return 0, nil, badRequest("ptr must not be empty")
+	}
+	ptr.PTR = req.PTR
+	return http.StatusOK, single(r, ptr), nil
+}
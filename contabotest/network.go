@@ -0,0 +1,195 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/mithucste30/contabo-api-golang/network"
+)
+
+// privateNetworkSize is the number of usable addresses of a private network
+const privateNetworkSize = 1021
+
+// AddPrivateNetwork stores a private network, filling in the ID and defaults of unset fields
+func (s *Server) AddPrivateNetwork(privateNetwork network.PrivateNetwork) network.PrivateNetwork {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillPrivateNetwork(&privateNetwork)
+	s.networks.add(privateNetwork)
+	return privateNetwork
+}
+
+// fillPrivateNetwork sets the ID and defaults of unset private network fields
+func (s *Server) fillPrivateNetwork(n *network.PrivateNetwork) {
+	if n.PrivateNetworkID == 0 {
+		n.PrivateNetworkID = s.newID()
+	}
+	fillOwner(&n.TenantID, &n.CustomerID)
+	if n.Region == "" {
+		n.Region = "EU"
+	}
+	if n.RegionName == "" {
+		n.RegionName = "European Union"
+	}
+	if n.DataCenter == "" {
+		n.DataCenter = "European Union 1"
+	}
+	if n.CIDR == "" {
+		n.CIDR = fmt.Sprintf("10.%d.0.0/22", n.PrivateNetworkID%250)
+	}
+	if n.Instances == nil {
+		n.Instances = []network.Instance{}
+	}
+	n.AvailableIPs = int64(privateNetworkSize - len(n.Instances))
+	if n.CreatedDate.IsZero() {
+		n.CreatedDate = now()
+	}
+}
+
+// registerNetwork adds the private network routes
+func (s *Server) registerNetwork(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/private-networks", s.listPrivateNetworks)
+	s.handle(mux, "POST /v1/private-networks", s.createPrivateNetwork)
+	s.handle(mux, "GET /v1/private-networks/{privateNetworkId}", s.getPrivateNetwork)
+	s.handle(mux, "PATCH /v1/private-networks/{privateNetworkId}", s.updatePrivateNetwork)
+	s.handle(mux, "DELETE /v1/private-networks/{privateNetworkId}", s.deletePrivateNetwork)
+	s.handle(mux, "POST /v1/private-networks/{privateNetworkId}/instances", s.assignInstances)
+	s.handle(mux, "DELETE /v1/private-networks/{privateNetworkId}/instances", s.unassignInstances)
+}
+
+// privateNetwork returns the private network of the request path
+func (s *Server) privateNetwork(r *http.Request) (*network.PrivateNetwork, error) {
+	id, err := parseID(r, "privateNetworkId")
+	if err != nil {
+		return nil, err
+	}
+	privateNetwork, ok := s.networks.get(fmt.Sprint(id))
+	if !ok {
+		return nil, notFound("PrivateNetwork", "privateNetworkId", fmt.Sprint(id))
+	}
+	return privateNetwork, nil
+}
+
+// assign adds instances to a private network
+func (s *Server) assign(n *network.PrivateNetwork, instanceIDs []int64) error {
+	for _, id := range instanceIDs {
+		if _, ok := s.instances.get(fmt.Sprint(id)); !ok {
+			return notFound("Instance", "instanceId", fmt.Sprint(id))
+		}
+		if slices.ContainsFunc(n.Instances, func(i network.Instance) bool { return i.InstanceID == id }) {
+			continue
+		}
+		n.Instances = append(n.Instances, network.Instance{
+			InstanceID: id,
+			PrivateIP:  fmt.Sprintf("10.%d.0.%d", n.PrivateNetworkID%250, len(n.Instances)+2),
+		})
+	}
+	n.AvailableIPs = int64(privateNetworkSize - len(n.Instances))
+	return nil
+}
+
+func (s *Server) listPrivateNetworks(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.networks.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createPrivateNetwork(r *http.Request) (int, interface{}, error) {
+	var req network.CreatePrivateNetworkRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" {
+		return 0, nil, badRequest("name must not be empty")
+	}
+
+	privateNetwork := network.PrivateNetwork{
+		Name:        req.Name,
+		Description: req.Description,
+		Region:      req.Region,
+	}
+	s.fillPrivateNetwork(&privateNetwork)
+	if err := s.assign(&privateNetwork, req.InstanceIDs); err != nil {
+		return 0, nil, err
+	}
+	s.networks.add(privateNetwork)
+
+	return http.StatusCreated, single(r, privateNetwork), nil
+}
+
+func (s *Server) getPrivateNetwork(r *http.Request) (int, interface{}, error) {
+	privateNetwork, err := s.privateNetwork(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, privateNetwork), nil
+}
+
+func (s *Server) updatePrivateNetwork(r *http.Request) (int, interface{}, error) {
+	privateNetwork, err := s.privateNetwork(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req network.PatchPrivateNetworkRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		privateNetwork.Name = *req.Name
+	}
+	if req.Description != nil {
+		privateNetwork.Description = *req.Description
+	}
+	return http.StatusOK, single(r, privateNetwork), nil
+}
+
+func (s *Server) deletePrivateNetwork(r *http.Request) (int, interface{}, error) {
+	privateNetwork, err := s.privateNetwork(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	s.networks.remove(fmt.Sprint(privateNetwork.PrivateNetworkID))
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) assignInstances(r *http.Request) (int, interface{}, error) {
+	privateNetwork, err := s.privateNetwork(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req network.AssignInstanceRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if err := s.assign(privateNetwork, req.InstanceIDs); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, single(r, privateNetwork), nil
+}
+
+// unassignInstances removes the instances listed in the body, or all
+// instances if the request has no body
+func (s *Server) unassignInstances(r *http.Request) (int, interface{}, error) {
+	privateNetwork, err := s.privateNetwork(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var req network.UnassignInstanceRequest
+	if r.ContentLength > 0 {
+		if err := decode(r, &req); err != nil {
+			return 0, nil, err
+		}
+	}
+	if len(req.InstanceIDs) == 0 {
+		privateNetwork.Instances = []network.Instance{}
+	} else {
+		privateNetwork.Instances = slices.DeleteFunc(privateNetwork.Instances, func(i network.Instance) bool {
+			return slices.Contains(req.InstanceIDs, i.InstanceID)
+		})
+	}
+	privateNetwork.AvailableIPs = int64(privateNetworkSize - len(privateNetwork.Instances))
+
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,114 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mithucste30/contabo-api-golang/secret"
+)
+
+// AddSecret stores a secret, filling in the ID and dates if unset
+func (s *Server) AddSecret(sec secret.Secret) secret.Secret {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillSecret(&sec)
+	s.secrets.add(sec)
+	return sec
+}
+
+// fillSecret sets the ID, owner and dates of a secret
+func (s *Server) fillSecret(sec *secret.Secret) {
+	if sec.SecretID == 0 {
+		sec.SecretID = s.newID()
+	}
+	fillOwner(&sec.TenantID, &sec.CustomerID)
+	if sec.CreatedDate.IsZero() {
+		sec.CreatedDate = now()
+	}
+	if sec.UpdatedDate.IsZero() {
+		sec.UpdatedDate = sec.CreatedDate
+	}
+}
+
+// registerSecret adds the secret routes
+func (s *Server) registerSecret(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/secrets", s.listSecrets)
+	s.handle(mux, "POST /v1/secrets", s.createSecret)
+	s.handle(mux, "GET /v1/secrets/{secretId}", s.getSecret)
+	s.handle(mux, "PATCH /v1/secrets/{secretId}", s.updateSecret)
+	s.handle(mux, "DELETE /v1/secrets/{secretId}", s.deleteSecret)
+}
+
+// secret returns the secret of the request path
+func (s *Server) secret(r *http.Request) (*secret.Secret, error) {
+	id, err := parseID(r, "secretId")
+	if err != nil {
+		return nil, err
+	}
+	sec, ok := s.secrets.get(fmt.Sprint(id))
+	if !ok {
+		return nil, notFound("Secret", "secretId", fmt.Sprint(id))
+	}
+	return sec, nil
+}
+
+func (s *Server) listSecrets(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.secrets.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createSecret(r *http.Request) (int, interface{}, error) {
+	var req secret.CreateSecretRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" || req.Value == "" {
+		return 0, nil, badRequest("name and value must not be empty")
+	}
+	if !req.Type.IsValid() {
+		return 0, nil, badRequest("type must be one of ssh, password")
+	}
+
+	sec := secret.Secret{Name: req.Name, Type: req.Type, Value: req.Value}
+	s.fillSecret(&sec)
+	s.secrets.add(sec)
+
+	return http.StatusCreated, single(r, sec), nil
+}
+
+func (s *Server) getSecret(r *http.Request) (int, interface{}, error) {
+	sec, err := s.secret(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, sec), nil
+}
+
+func (s *Server) updateSecret(r *http.Request) (int, interface{}, error) {
+	sec, err := s.secret(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req secret.PatchSecretRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		sec.Name = *req.Name
+	}
+	if req.Value != nil {
+		sec.Value = *req.Value
+	}
+	sec.UpdatedDate = now()
+	return http.StatusOK, single(r, sec), nil
+}
+
+func (s *Server) deleteSecret(r *http.Request) (int, interface{}, error) {
+	sec, err := s.secret(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	s.secrets.remove(fmt.Sprint(sec.SecretID))
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,359 @@
+// Package contabotest provides an in-memory fake of the Contabo API for
+// tests. The server implements the token endpoint and every route the SDK
+// calls, with pagination envelopes, error bodies and status transitions
+// shaped like the real API, so tests exercise the SDK's real request and
+// response code paths.
+package contabotest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/dns"
+	"github.com/mithucste30/contabo-api-golang/network"
+	"github.com/mithucste30/contabo-api-golang/secret"
+	"github.com/mithucste30/contabo-api-golang/storage"
+	"github.com/mithucste30/contabo-api-golang/tag"
+	"github.com/mithucste30/contabo-api-golang/user"
+)
+
+// Credentials accepted by a new Server
+const (
+	ClientID     = "contabotest-client"
+	ClientSecret = "contabotest-secret"
+	Username     = "api-user@example.com"
+	Password     = "contabotest-password"
+)
+
+// TenantID and CustomerID are set on every resource created by the server
+const (
+	TenantID   = "DE"
+	CustomerID = "54321"
+)
+
+// tokenPath is the path of the OAuth2 token endpoint
+const tokenPath = "/auth/realms/contabo/protocol/openid-connect/token"
+
+// Server is a running fake Contabo API
+type Server struct {
+	*httptest.Server
+
+	// Credentials accepted by the token endpoint (default to the package constants)
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+
+	// Lifetime of issued access tokens (defaults to 5 minutes)
+	TokenTTL time.Duration
+
+	mu            sync.Mutex
+	accessTokens  map[string]time.Time
+	refreshTokens map[string]bool
+	nextID        int64
+	transitions   map[string]string // pending status changes, applied on the next read
+
+	instances      store[compute.Instance]
+	snapshots      store[compute.Snapshot]
+	images         store[compute.Image]
+	objectStorages store[storage.ObjectStorage]
+	networks       store[network.PrivateNetwork]
+	zones          store[dns.Zone]
+	records        map[string]*store[dns.Record] // by zone name
+	ptrRecords     store[dns.PTRRecord]
+	secrets        store[secret.Secret]
+	tags           store[tag.Tag]
+	assignments    map[string]bool // tagId/resourceType/resourceId
+	users          store[user.User]
+	roles          store[user.Role]
+}
+
+// NewServer starts a fake Contabo API. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		ClientID:      ClientID,
+		ClientSecret:  ClientSecret,
+		Username:      Username,
+		Password:      Password,
+		TokenTTL:      5 * time.Minute,
+		accessTokens:  make(map[string]time.Time),
+		refreshTokens: make(map[string]bool),
+		nextID:        100000000,
+		transitions:   make(map[string]string),
+		records:       make(map[string]*store[dns.Record]),
+		assignments:   make(map[string]bool),
+	}
+	s.instances.key = func(i *compute.Instance) string { return fmt.Sprint(i.InstanceID) }
+	s.snapshots.key = func(sn *compute.Snapshot) string { return fmt.Sprint(sn.InstanceID, "/", sn.SnapshotID) }
+	s.images.key = func(i *compute.Image) string { return i.ImageID }
+	s.objectStorages.key = func(o *storage.ObjectStorage) string { return o.ObjectStorageID }
+	s.networks.key = func(n *network.PrivateNetwork) string { return fmt.Sprint(n.PrivateNetworkID) }
+	s.zones.key = func(z *dns.Zone) string { return z.Name }
+	s.ptrRecords.key = func(p *dns.PTRRecord) string { return p.IPAddress }
+	s.secrets.key = func(sec *secret.Secret) string { return fmt.Sprint(sec.SecretID) }
+	s.tags.key = func(t *tag.Tag) string { return fmt.Sprint(t.TagID) }
+	s.users.key = func(u *user.User) string { return u.UserID }
+	s.roles.key = func(r *user.Role) string { return fmt.Sprint(r.RoleID) }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+tokenPath, s.handleToken)
+	s.registerCompute(mux)
+	s.registerStorage(mux)
+	s.registerNetwork(mux)
+	s.registerDNS(mux)
+	s.registerSecret(mux)
+	s.registerTag(mux)
+	s.registerUser(mux)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config returns an SDK configuration pointed at the server with valid credentials
+func (s *Server) Config() *contabo.Config {
+	config := contabo.NewConfig(s.ClientID, s.ClientSecret, s.Username, s.Password)
+	config.AuthURL = s.URL + tokenPath
+	config.BaseURL = s.URL
+	return config
+}
+
+// NewSDK creates an SDK using the server. Options are passed on to contabo.NewSDK.
+func (s *Server) NewSDK(opts ...contabo.Option) (*contabo.SDK, error) {
+	return contabo.NewSDK(s.Config(), opts...)
+}
+
+// NewSDK starts a server for the duration of the test and returns an SDK
+// pointed at it. The server is closed when the test ends.
+func NewSDK(t testing.TB, opts ...contabo.Option) (*contabo.SDK, *Server) {
+	t.Helper()
+
+	s := NewServer()
+	t.Cleanup(s.Close)
+
+	sdk, err := s.NewSDK(opts...)
+	if err != nil {
+		t.Fatalf("contabotest: creating SDK: %v", err)
+	}
+	return sdk, s
+}
+
+// ExpireTokens invalidates all issued access tokens, so the next API call
+// is rejected with 401 and the SDK has to re-authenticate
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.accessTokens)
+}
+
+// handleToken implements the password and refresh token grants
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		if r.PostForm.Get("username") != s.Username || r.PostForm.Get("password") != s.Password {
+			writeTokenError(w, http.StatusUnauthorized, "invalid_grant")
+			return
+		}
+	case "refresh_token":
+		refreshToken := r.PostForm.Get("refresh_token")
+		if !s.refreshTokens[refreshToken] {
+			writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		delete(s.refreshTokens, refreshToken)
+	default:
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	accessToken, refreshToken := randomToken(), randomToken()
+	s.accessTokens[accessToken] = time.Now().Add(s.TokenTTL)
+	s.refreshTokens[refreshToken] = true
+
+	writeJSON(w, http.StatusOK, contabo.TokenResponse{
+		AccessToken:      accessToken,
+		ExpiresIn:        int(s.TokenTTL.Seconds()),
+		RefreshExpiresIn: int(6 * s.TokenTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		Scope:            "openid",
+	})
+}
+
+// writeTokenError writes an OAuth2 error response
+func writeTokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+// randomToken returns an opaque random token
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// apiError is an error response of a handler
+type apiError struct {
+	status  int
+	message string
+}
+
+// Error implements the error interface
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// notFound returns the 404 error of a missing resource
+func notFound(kind, field, id string) error {
+	return &apiError{http.StatusNotFound, fmt.Sprintf("Entry %s not found by %s %s", kind, field, id)}
+}
+
+// badRequest returns a 400 validation error
+func badRequest(format string, args ...interface{}) error {
+	return &apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+// handler serves an authenticated API request; a nil result means 204 No Content
+type handler func(r *http.Request) (status int, result interface{}, err error)
+
+// handle registers an API route behind authentication and the state lock
+func (s *Server) handle(mux *http.ServeMux, pattern string, h handler) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("x-request-id")
+		if requestID == "" {
+			writeError(w, &apiError{http.StatusBadRequest, "x-request-id header is required"})
+			return
+		}
+		w.Header().Set("x-request-id", requestID)
+		if traceID := r.Header.Get("x-trace-id"); traceID != "" {
+			w.Header().Set("x-trace-id", traceID)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"statusCode": http.StatusUnauthorized,
+				"message":    "Unauthorized",
+			})
+			return
+		}
+
+		status, result, err := h(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if result == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, status, result)
+	})
+}
+
+// authorized reports whether r carries a valid access token
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	expiry, ok := s.accessTokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+// writeError writes Contabo's JSON error envelope
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		apiErr = &apiError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, apiErr.status, map[string]interface{}{
+		"statusCode": apiErr.status,
+		"message":    apiErr.message,
+		"error":      http.StatusText(apiErr.status),
+	})
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// decode reads the JSON request body into v
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// single wraps one resource in the data envelope
+func single(r *http.Request, item interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"data":   []interface{}{item},
+		"_links": map[string]string{"self": r.URL.Path},
+	}
+}
+
+// newID returns the next numeric resource ID
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// newUUID returns a random resource ID in UUID format
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// now returns the timestamp used for created and updated dates
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// transition schedules a status change applied when the resource is next read
+func transition[S ~string](s *Server, kind, id string, status S) {
+	s.transitions[kind+"/"+id] = string(status)
+}
+
+// advance applies a pending status change of a resource being read
+func advance[S ~string](s *Server, kind, id string, status *S) {
+	key := kind + "/" + id
+	if next, ok := s.transitions[key]; ok {
+		*status = S(next)
+		delete(s.transitions, key)
+	}
+}
@@ -0,0 +1,451 @@
+package contabotest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/tag"
+)
+
+// tokenPath is the path of the server's token endpoint
+const tokenPath = "/auth/realms/contabo/protocol/openid-connect/token"
+
+// passwordGrant returns the form of a valid password grant
+func passwordGrant() url.Values {
+	return url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {contabotest.ClientID},
+		"client_secret": {contabotest.ClientSecret},
+		"username":      {contabotest.Username},
+		"password":      {contabotest.Password},
+	}
+}
+
+// requestToken posts form to the token endpoint and returns the status and
+// the decoded body
+func requestToken(t *testing.T, server *contabotest.Server, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+	resp, err := http.PostForm(server.URL+tokenPath, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding token response: %v", err)
+	}
+	return resp.StatusCode, body
+}
+
+// accessToken returns a fresh access token from the server
+func accessToken(t *testing.T, server *contabotest.Server) string {
+	t.Helper()
+	status, body := requestToken(t, server, passwordGrant())
+	if status != http.StatusOK {
+		t.Fatalf("token request status = %d, want 200", status)
+	}
+	return body["access_token"].(string)
+}
+
+// get makes an API request with token, decoding the body into v if set
+func get(t *testing.T, server *contabotest.Server, token, path string, v interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("x-request-id", "req-1")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decoding %s: %v", path, err)
+		}
+	}
+	return resp
+}
+
+// listEnvelope is the list response shape returned by the server
+type listEnvelope struct {
+	Pagination struct {
+		Size          int `json:"size"`
+		TotalElements int `json:"totalElements"`
+		TotalPages    int `json:"totalPages"`
+		Page          int `json:"page"`
+		Number        int `json:"number"`
+	} `json:"_pagination"`
+	Links map[string]string `json:"_links"`
+	Data  []tag.Tag         `json:"data"`
+}
+
+func TestPagination(t *testing.T) {
+	server := contabotest.NewServer()
+	defer server.Close()
+	for i := range 7 {
+		color := "#000000"
+		if i%2 == 1 {
+			color = "#FFFFFF"
+		}
+		server.AddTag(tag.Tag{Name: fmt.Sprintf("tag-%d", i+1), Color: color})
+	}
+	token := accessToken(t, server)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantNames  []string
+		wantSize   int
+		wantTotal  int
+		wantPages  int
+		wantPage   int
+		wantLinks  map[string]string
+		wantStatus int
+	}{
+		{
+			name:      "defaults",
+			wantNames: []string{"tag-1", "tag-2", "tag-3", "tag-4", "tag-5", "tag-6", "tag-7"},
+			wantSize:  100, wantTotal: 7, wantPages: 1, wantPage: 1,
+			wantLinks: map[string]string{
+				"self":  "/v1/tags?page=1&size=100",
+				"first": "/v1/tags?page=1&size=100",
+				"last":  "/v1/tags?page=1&size=100",
+			},
+		},
+		{
+			name:      "first page",
+			query:     "?size=3",
+			wantNames: []string{"tag-1", "tag-2", "tag-3"},
+			wantSize:  3, wantTotal: 7, wantPages: 3, wantPage: 1,
+			wantLinks: map[string]string{
+				"self":  "/v1/tags?page=1&size=3",
+				"first": "/v1/tags?page=1&size=3",
+				"next":  "/v1/tags?page=2&size=3",
+				"last":  "/v1/tags?page=3&size=3",
+			},
+		},
+		{
+			name:      "middle page",
+			query:     "?page=2&size=3",
+			wantNames: []string{"tag-4", "tag-5", "tag-6"},
+			wantSize:  3, wantTotal: 7, wantPages: 3, wantPage: 2,
+			wantLinks: map[string]string{
+				"self":     "/v1/tags?page=2&size=3",
+				"first":    "/v1/tags?page=1&size=3",
+				"previous": "/v1/tags?page=1&size=3",
+				"next":     "/v1/tags?page=3&size=3",
+				"last":     "/v1/tags?page=3&size=3",
+			},
+		},
+		{
+			name:      "last page",
+			query:     "?page=3&size=3",
+			wantNames: []string{"tag-7"},
+			wantSize:  3, wantTotal: 7, wantPages: 3, wantPage: 3,
+			wantLinks: map[string]string{
+				"self":     "/v1/tags?page=3&size=3",
+				"first":    "/v1/tags?page=1&size=3",
+				"previous": "/v1/tags?page=2&size=3",
+				"last":     "/v1/tags?page=3&size=3",
+			},
+		},
+		{
+			name:      "past the last page",
+			query:     "?page=5&size=3",
+			wantNames: []string{},
+			wantSize:  3, wantTotal: 7, wantPages: 3, wantPage: 5,
+			wantLinks: map[string]string{
+				"self":     "/v1/tags?page=5&size=3",
+				"first":    "/v1/tags?page=1&size=3",
+				"previous": "/v1/tags?page=4&size=3",
+				"last":     "/v1/tags?page=3&size=3",
+			},
+		},
+		{
+			name:      "filter keeps its parameter in links",
+			query:     "?color=%23ffffff&size=2",
+			wantNames: []string{"tag-2", "tag-4"},
+			wantSize:  2, wantTotal: 3, wantPages: 2, wantPage: 1,
+			wantLinks: map[string]string{
+				"self":  "/v1/tags?color=%23ffffff&page=1&size=2",
+				"first": "/v1/tags?color=%23ffffff&page=1&size=2",
+				"next":  "/v1/tags?color=%23ffffff&page=2&size=2",
+				"last":  "/v1/tags?color=%23ffffff&page=2&size=2",
+			},
+		},
+		{
+			name:      "no matches",
+			query:     "?name=missing",
+			wantNames: []string{},
+			wantSize:  100, wantTotal: 0, wantPages: 0, wantPage: 1,
+			wantLinks: map[string]string{
+				"self":  "/v1/tags?name=missing&page=1&size=100",
+				"first": "/v1/tags?name=missing&page=1&size=100",
+				"last":  "/v1/tags?name=missing&page=1&size=100",
+			},
+		},
+		{name: "zero page", query: "?page=0", wantStatus: http.StatusBadRequest},
+		{name: "invalid size", query: "?size=abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantStatus != 0 {
+				var errBody map[string]interface{}
+				resp := get(t, server, token, "/v1/tags"+tt.query, &errBody)
+				if resp.StatusCode != tt.wantStatus || errBody["statusCode"] != float64(tt.wantStatus) {
+					t.Errorf("response = %d %v, want %d with the error envelope", resp.StatusCode, errBody, tt.wantStatus)
+				}
+				return
+			}
+
+			var got listEnvelope
+			resp := get(t, server, token, "/v1/tags"+tt.query, &got)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+
+			names := []string{}
+			for _, tg := range got.Data {
+				names = append(names, tg.Name)
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("data = %v, want %v", names, tt.wantNames)
+			}
+			p := got.Pagination
+			if p.Size != tt.wantSize || p.TotalElements != tt.wantTotal || p.TotalPages != tt.wantPages || p.Page != tt.wantPage || p.Number != tt.wantPage {
+				t.Errorf("_pagination = %+v, want size %d, %d elements, %d pages, page %d",
+					p, tt.wantSize, tt.wantTotal, tt.wantPages, tt.wantPage)
+			}
+			if !maps.Equal(got.Links, tt.wantLinks) {
+				t.Errorf("_links = %v, want %v", got.Links, tt.wantLinks)
+			}
+		})
+	}
+}
+
+func TestPaginationSDK(t *testing.T) {
+	sdk, server := contabotest.NewSDK(t)
+	for i := range 25 {
+		server.AddTag(tag.Tag{Name: fmt.Sprintf("tag-%02d", i+1)})
+	}
+	ctx := context.Background()
+
+	page, err := sdk.Tag.ListTags(ctx, &tag.ListTagsOptions{ListOptions: tag.ListOptions{Page: 3, Size: 10}})
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(page.Data) != 5 || page.Data[0].Name != "tag-21" {
+		t.Errorf("ListTags() page 3 = %d tags starting at %q, want 5 starting at tag-21", len(page.Data), page.Data[0].Name)
+	}
+	if page.Pagination.TotalElements != 25 || page.Pagination.TotalPages != 3 || page.Pagination.Number != 3 {
+		t.Errorf("ListTags() pagination = %+v, want 25 elements on 3 pages", page.Pagination)
+	}
+	if page.Links.Next != "" || page.Links.Previous == "" {
+		t.Errorf("ListTags() links = %+v, want previous but no next on the last page", page.Links)
+	}
+
+	for _, concurrency := range []int{1, 4} {
+		all, err := sdk.Tag.ListAllTags(ctx, &tag.ListTagsOptions{ListOptions: tag.ListOptions{Size: 4, Concurrency: concurrency}})
+		if err != nil {
+			t.Fatalf("ListAllTags() error = %v", err)
+		}
+		if len(all) != 25 {
+			t.Fatalf("ListAllTags() with concurrency %d = %d tags, want 25", concurrency, len(all))
+		}
+		for i, tg := range all {
+			if want := fmt.Sprintf("tag-%02d", i+1); tg.Name != want {
+				t.Errorf("ListAllTags() with concurrency %d: tag %d = %q, want %q", concurrency, i, tg.Name, want)
+				break
+			}
+		}
+	}
+}
+
+func TestTokenGrants(t *testing.T) {
+	server := contabotest.NewServer()
+	defer server.Close()
+
+	with := func(key, value string) url.Values {
+		form := passwordGrant()
+		form.Set(key, value)
+		return form
+	}
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		wantStatus int
+		wantError  string
+	}{
+		{"password grant", passwordGrant(), http.StatusOK, ""},
+		{"wrong client secret", with("client_secret", "wrong"), http.StatusUnauthorized, "invalid_client"},
+		{"unknown client", with("client_id", "other"), http.StatusUnauthorized, "invalid_client"},
+		{"wrong password", with("password", "wrong"), http.StatusUnauthorized, "invalid_grant"},
+		{"wrong username", with("username", "other@example.com"), http.StatusUnauthorized, "invalid_grant"},
+		{"unknown refresh token", with("grant_type", "refresh_token"), http.StatusBadRequest, "invalid_grant"},
+		{"unsupported grant", with("grant_type", "client_credentials"), http.StatusBadRequest, "unsupported_grant_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := requestToken(t, server, tt.form)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantError != "" {
+				if body["error"] != tt.wantError {
+					t.Errorf("error = %v, want %s", body["error"], tt.wantError)
+				}
+				return
+			}
+			if body["access_token"] == "" || body["refresh_token"] == "" || body["token_type"] != "Bearer" || body["expires_in"] != float64(300) {
+				t.Errorf("token response = %v, want Bearer tokens expiring in 300 seconds", body)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenSingleUse(t *testing.T) {
+	server := contabotest.NewServer()
+	defer server.Close()
+
+	_, body := requestToken(t, server, passwordGrant())
+	refresh := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {contabotest.ClientID},
+		"client_secret": {contabotest.ClientSecret},
+		"refresh_token": {body["refresh_token"].(string)},
+	}
+
+	status, refreshed := requestToken(t, server, refresh)
+	if status != http.StatusOK {
+		t.Fatalf("refresh status = %d, want 200", status)
+	}
+	if refreshed["refresh_token"] == body["refresh_token"] {
+		t.Error("refresh returned the same refresh token")
+	}
+	if resp := get(t, server, refreshed["access_token"].(string), "/v1/tags", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("refreshed access token rejected with %d", resp.StatusCode)
+	}
+
+	status, reused := requestToken(t, server, refresh)
+	if status != http.StatusBadRequest || reused["error"] != "invalid_grant" {
+		t.Errorf("reused refresh token = %d %v, want 400 invalid_grant", status, reused)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(t *testing.T, server *contabotest.Server) string
+	}{
+		{"no token", func(*testing.T, *contabotest.Server) string { return "" }},
+		{"unknown token", func(*testing.T, *contabotest.Server) string { return "not-a-token" }},
+		{"expired by TTL", func(t *testing.T, server *contabotest.Server) string {
+			server.TokenTTL = 50 * time.Millisecond
+			token := accessToken(t, server)
+			time.Sleep(100 * time.Millisecond)
+			return token
+		}},
+		{"expired by ExpireTokens", func(t *testing.T, server *contabotest.Server) string {
+			token := accessToken(t, server)
+			server.ExpireTokens()
+			return token
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := contabotest.NewServer()
+			defer server.Close()
+
+			var body map[string]interface{}
+			resp := get(t, server, tt.token(t, server), "/v1/tags", &body)
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", resp.StatusCode)
+			}
+			if got := resp.Header.Get("WWW-Authenticate"); got != `Bearer error="invalid_token"` {
+				t.Errorf("WWW-Authenticate = %q, want invalid_token", got)
+			}
+			if body["statusCode"] != float64(401) || body["message"] != "Unauthorized" {
+				t.Errorf("body = %v, want the 401 envelope", body)
+			}
+		})
+	}
+}
+
+func TestMissingRequestID(t *testing.T) {
+	server := contabotest.NewServer()
+	defer server.Close()
+
+	// The header is checked before authentication
+	resp, err := http.Get(server.URL + "/v1/tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(fmt.Sprint(body["message"]), "x-request-id") {
+		t.Errorf("response = %d %v, want 400 naming x-request-id", resp.StatusCode, body)
+	}
+}
+
+// countingTransport counts the token requests passing through it
+type countingTransport struct {
+	tokens atomic.Int64
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == tokenPath {
+		c.tokens.Add(1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestExpireTokensReauthenticates(t *testing.T) {
+	transport := &countingTransport{}
+	sdk, server := contabotest.NewSDK(t, contabo.WithTransport(transport))
+	server.AddTag(tag.Tag{Name: "web"})
+	ctx := context.Background()
+
+	if _, err := sdk.Tag.ListTags(ctx, nil); err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if _, err := sdk.Tag.ListTags(ctx, nil); err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if n := transport.tokens.Load(); n != 1 {
+		t.Fatalf("%d token requests before expiry, want the token reused", n)
+	}
+
+	server.ExpireTokens()
+	tags, err := sdk.Tag.ListTags(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTags() after ExpireTokens error = %v, want a transparent re-authentication", err)
+	}
+	if len(tags.Data) != 1 || tags.Data[0].Name != "web" {
+		t.Errorf("ListTags() after ExpireTokens = %+v, want the stored tag", tags.Data)
+	}
+	if n := transport.tokens.Load(); n != 2 {
+		t.Errorf("%d token requests after expiry, want 2", n)
+	}
+}
@@ -0,0 +1,202 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mithucste30/contabo-api-golang/storage"
+)
+
+// Object storage statuses used by the server
+const (
+	StorageStatusProvisioning = "PROVISIONING"
+	StorageStatusUpgrading    = "UPGRADING"
+	StorageStatusReady        = "READY"
+)
+
+// AddObjectStorage stores an object storage, filling in the ID and defaults of unset fields
+func (s *Server) AddObjectStorage(objectStorage storage.ObjectStorage) storage.ObjectStorage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fillObjectStorage(&objectStorage)
+	s.objectStorages.add(objectStorage)
+	return objectStorage
+}
+
+// fillObjectStorage sets the ID and defaults of unset object storage fields
+func fillObjectStorage(o *storage.ObjectStorage) {
+	if o.ObjectStorageID == "" {
+		o.ObjectStorageID = newUUID()
+	}
+	fillOwner(&o.TenantID, &o.CustomerID)
+	if o.Status == "" {
+		o.Status = StorageStatusReady
+	}
+	if o.Region == "" {
+		o.Region = "EU"
+	}
+	if o.DataCenter == "" {
+		o.DataCenter = "European Union 2"
+	}
+	if o.S3URL == "" {
+		o.S3URL = fmt.Sprintf("https://%s.contabostorage.com", strings.ToLower(o.Region))
+	}
+	if o.S3TenantID == "" {
+		o.S3TenantID = strings.ReplaceAll(o.ObjectStorageID, "-", "")
+	}
+	if o.AutoScaling.State == "" {
+		o.AutoScaling.State = "disabled"
+	}
+	if o.TotalPurchasedSpaceTB == 0 {
+		o.TotalPurchasedSpaceTB = 0.25
+	}
+	if o.CreatedDate.IsZero() {
+		o.CreatedDate = now()
+	}
+}
+
+// registerStorage adds the object storage routes
+func (s *Server) registerStorage(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/object-storages", s.listObjectStorages)
+	s.handle(mux, "POST /v1/object-storages", s.createObjectStorage)
+	s.handle(mux, "GET /v1/object-storages/{objectStorageId}", s.getObjectStorage)
+	s.handle(mux, "PATCH /v1/object-storages/{objectStorageId}", s.updateObjectStorage)
+	s.handle(mux, "POST /v1/object-storages/{objectStorageId}/resize", s.resizeObjectStorage)
+	s.handle(mux, "POST /v1/object-storages/{objectStorageId}/cancel", s.cancelObjectStorage)
+	s.handle(mux, "GET /v1/object-storages/{objectStorageId}/stats", s.objectStorageStats)
+	s.handle(mux, "GET /v1/users/object-storage-credentials/{objectStorageId}", s.objectStorageCredentials)
+}
+
+// objectStorage returns the object storage of the request path, applying pending status changes
+func (s *Server) objectStorage(r *http.Request) (*storage.ObjectStorage, error) {
+	id := r.PathValue("objectStorageId")
+	objectStorage, ok := s.objectStorages.get(id)
+	if !ok {
+		return nil, notFound("ObjectStorage", "objectStorageId", id)
+	}
+	advance(s, "objectStorage", id, &objectStorage.Status)
+	return objectStorage, nil
+}
+
+func (s *Server) listObjectStorages(r *http.Request) (int, interface{}, error) {
+	for i := range s.objectStorages.items {
+		o := &s.objectStorages.items[i]
+		advance(s, "objectStorage", o.ObjectStorageID, &o.Status)
+	}
+	page, err := paginate(r, s.objectStorages.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createObjectStorage(r *http.Request) (int, interface{}, error) {
+	var req storage.CreateObjectStorageRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Region == "" || req.TotalPurchasedSpaceTB <= 0 {
+		return 0, nil, badRequest("region and totalPurchasedSpaceTB are required")
+	}
+
+	objectStorage := storage.ObjectStorage{
+		Region:                req.Region,
+		TotalPurchasedSpaceTB: req.TotalPurchasedSpaceTB,
+		DisplayName:           req.DisplayName,
+		Status:                StorageStatusProvisioning,
+	}
+	if req.AutoScaling != nil {
+		objectStorage.AutoScaling = storage.AutoScaling{State: req.AutoScaling.State, SizeLimitTB: req.AutoScaling.SizeLimitTB}
+	}
+	fillObjectStorage(&objectStorage)
+	s.objectStorages.add(objectStorage)
+	transition(s, "objectStorage", objectStorage.ObjectStorageID, StorageStatusReady)
+
+	return http.StatusCreated, single(r, objectStorage), nil
+}
+
+func (s *Server) getObjectStorage(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, objectStorage), nil
+}
+
+func (s *Server) updateObjectStorage(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req storage.PatchObjectStorageRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.DisplayName != nil {
+		objectStorage.DisplayName = *req.DisplayName
+	}
+	if req.AutoScaling != nil {
+		objectStorage.AutoScaling = storage.AutoScaling{State: req.AutoScaling.State, SizeLimitTB: req.AutoScaling.SizeLimitTB}
+	}
+	return http.StatusOK, single(r, objectStorage), nil
+}
+
+func (s *Server) resizeObjectStorage(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req storage.UpgradeObjectStorageRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.TotalPurchasedSpaceTB > 0 {
+		if req.TotalPurchasedSpaceTB < objectStorage.TotalPurchasedSpaceTB {
+			return 0, nil, badRequest("totalPurchasedSpaceTB cannot be decreased")
+		}
+		objectStorage.TotalPurchasedSpaceTB = req.TotalPurchasedSpaceTB
+		objectStorage.Status = StorageStatusUpgrading
+		transition(s, "objectStorage", objectStorage.ObjectStorageID, StorageStatusReady)
+	}
+	if req.AutoScaling != nil {
+		objectStorage.AutoScaling = storage.AutoScaling{State: req.AutoScaling.State, SizeLimitTB: req.AutoScaling.SizeLimitTB}
+	}
+	return http.StatusOK, single(r, objectStorage), nil
+}
+
+func (s *Server) cancelObjectStorage(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if objectStorage.CancelDate == "" {
+		objectStorage.CancelDate = time.Now().AddDate(0, 1, 0).Format(time.DateOnly)
+	}
+	return http.StatusOK, single(r, objectStorage), nil
+}
+
+func (s *Server) objectStorageStats(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, storage.ObjectStorageStats{
+		ObjectStorageID: objectStorage.ObjectStorageID,
+	}), nil
+}
+
+func (s *Server) objectStorageCredentials(r *http.Request) (int, interface{}, error) {
+	objectStorage, err := s.objectStorage(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	// Derive stable keys from the ID, so repeated calls return the same credentials
+	id := strings.ReplaceAll(objectStorage.ObjectStorageID, "-", "")
+	return http.StatusOK, single(r, storage.Credentials{
+		TenantID:    objectStorage.TenantID,
+		CustomerID:  objectStorage.CustomerID,
+		AccessKey:   strings.ToUpper(id[:20]),
+		SecretKey:   id,
+		DisplayName: objectStorage.DisplayName,
+	}), nil
+}
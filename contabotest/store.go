@@ -0,0 +1,157 @@
+package contabotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultPageSize is the page size used when a list request sets none
+const defaultPageSize = 100
+
+// store holds resources of one kind in creation order
+type store[T any] struct {
+	items []T
+	key   func(*T) string
+}
+
+// get returns the resource with the given key. The pointer is only valid
+// until the store is modified.
+func (s *store[T]) get(key string) (*T, bool) {
+	for i := range s.items {
+		if s.key(&s.items[i]) == key {
+			return &s.items[i], true
+		}
+	}
+	return nil, false
+}
+
+// add appends a resource
+func (s *store[T]) add(item T) {
+	s.items = append(s.items, item)
+}
+
+// remove deletes the resource with the given key
+func (s *store[T]) remove(key string) bool {
+	for i := range s.items {
+		if s.key(&s.items[i]) == key {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// listParams are the query parameters handled by every list endpoint
+var listParams = map[string]bool{"page": true, "size": true, "orderBy": true}
+
+// paginate filters items by the request's query and returns the requested
+// page in Contabo's list envelope. Query parameters other than page, size and
+// orderBy filter on the JSON field of the same name (case-insensitive for
+// strings), or on the singular field for a comma-separated list such as
+// instanceIds; orderBy is ignored.
+func paginate[T any](r *http.Request, items []T) (map[string]interface{}, error) {
+	query := r.URL.Query()
+
+	page, size := 1, defaultPageSize
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, badRequest("page must be a positive integer")
+		}
+		page = n
+	}
+	if v := query.Get("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, badRequest("size must be a positive integer")
+		}
+		size = n
+	}
+
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if matches(item, query) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	total := len(filtered)
+	totalPages := (total + size - 1) / size
+	start := min((page-1)*size, total)
+	end := min(start+size, total)
+
+	link := func(p int) string {
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = values
+		}
+		q.Set("page", strconv.Itoa(p))
+		q.Set("size", strconv.Itoa(size))
+		return r.URL.Path + "?" + q.Encode()
+	}
+	links := map[string]string{
+		"self":  link(page),
+		"first": link(1),
+		"last":  link(max(totalPages, 1)),
+	}
+	if page > 1 {
+		links["previous"] = link(page - 1)
+	}
+	if page < totalPages {
+		links["next"] = link(page + 1)
+	}
+
+	return map[string]interface{}{
+		"_pagination": map[string]int{
+			"size":          size,
+			"totalElements": total,
+			"totalPages":    totalPages,
+			"page":          page,
+			"number":        page,
+		},
+		"_links": links,
+		"data":   filtered[start:end],
+	}, nil
+}
+
+// matches reports whether item has the values of all filter parameters
+func matches(item interface{}, query url.Values) bool {
+	var fields map[string]interface{}
+	for key := range query {
+		if listParams[key] {
+			continue
+		}
+		if fields == nil {
+			data, err := json.Marshal(item)
+			if err != nil {
+				return false
+			}
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			if dec.Decode(&fields) != nil {
+				return false
+			}
+		}
+		wanted := []string{query.Get(key)}
+		value, ok := fields[key]
+		if !ok {
+			// Plural filters such as instanceIds take a comma-separated list
+			// of values of the singular field
+			value, ok = fields[strings.TrimSuffix(key, "s")]
+			wanted = strings.Split(query.Get(key), ",")
+		}
+		if !ok {
+			continue // unknown filters are ignored, like by the API
+		}
+		if !slices.ContainsFunc(wanted, func(w string) bool { return strings.EqualFold(fmt.Sprint(value), w) }) {
+			return false
+		}
+	}
+	return true
+}
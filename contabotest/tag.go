@@ -0,0 +1,175 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/tag"
+)
+
+// AddTag stores a tag, filling in the ID and dates if unset
+func (s *Server) AddTag(t tag.Tag) tag.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillTag(&t)
+	s.tags.add(t)
+	return t
+}
+
+// TagAssigned reports whether a tag is assigned to a resource
+func (s *Server) TagAssigned(tagID int64, resourceType, resourceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.assignments[assignmentKey(fmt.Sprint(tagID), resourceType, resourceID)]
+}
+
+// fillTag sets the ID, owner and dates of a tag
+func (s *Server) fillTag(t *tag.Tag) {
+	if t.TagID == 0 {
+		t.TagID = s.newID()
+	}
+	fillOwner(&t.TenantID, &t.CustomerID)
+	if t.Color == "" {
+		t.Color = "#0A78C3"
+	}
+	if t.CreatedDate.IsZero() {
+		t.CreatedDate = now()
+	}
+	if t.UpdatedDate.IsZero() {
+		t.UpdatedDate = t.CreatedDate
+	}
+}
+
+// assignmentKey identifies a tag assignment
+func assignmentKey(tagID, resourceType, resourceID string) string {
+	return tagID + "/" + resourceType + "/" + resourceID
+}
+
+// registerTag adds the tag and tag assignment routes
+func (s *Server) registerTag(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/tags", s.listTags)
+	s.handle(mux, "POST /v1/tags", s.createTag)
+	s.handle(mux, "GET /v1/tags/{tagId}", s.getTag)
+	s.handle(mux, "PATCH /v1/tags/{tagId}", s.updateTag)
+	s.handle(mux, "DELETE /v1/tags/{tagId}", s.deleteTag)
+	s.handle(mux, "PUT /v1/tags/{tagId}/assignments/{resourceType}/{resourceId}", s.assignTag)
+	s.handle(mux, "DELETE /v1/tags/{tagId}/assignments/{resourceType}/{resourceId}", s.unassignTag)
+}
+
+// tag returns the tag of the request path
+func (s *Server) tag(r *http.Request) (*tag.Tag, error) {
+	id, err := parseID(r, "tagId")
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.tags.get(fmt.Sprint(id))
+	if !ok {
+		return nil, notFound("Tag", "tagId", fmt.Sprint(id))
+	}
+	return t, nil
+}
+
+func (s *Server) listTags(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.tags.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createTag(r *http.Request) (int, interface{}, error) {
+	var req tag.CreateTagRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" {
+		return 0, nil, badRequest("name must not be empty")
+	}
+	for _, t := range s.tags.items {
+		if strings.EqualFold(t.Name, req.Name) {
+			return 0, nil, &apiError{http.StatusConflict, fmt.Sprintf("tag %s already exists", req.Name)}
+		}
+	}
+
+	t := tag.Tag{Name: req.Name, Color: req.Color}
+	s.fillTag(&t)
+	s.tags.add(t)
+
+	return http.StatusCreated, single(r, t), nil
+}
+
+func (s *Server) getTag(r *http.Request) (int, interface{}, error) {
+	t, err := s.tag(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, t), nil
+}
+
+func (s *Server) updateTag(r *http.Request) (int, interface{}, error) {
+	t, err := s.tag(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req tag.PatchTagRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		t.Name = *req.Name
+	}
+	if req.Color != nil {
+		t.Color = *req.Color
+	}
+	t.UpdatedDate = now()
+	return http.StatusOK, single(r, t), nil
+}
+
+func (s *Server) deleteTag(r *http.Request) (int, interface{}, error) {
+	t, err := s.tag(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	prefix := fmt.Sprint(t.TagID) + "/"
+	for key := range s.assignments {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.assignments, key)
+		}
+	}
+	s.tags.remove(fmt.Sprint(t.TagID))
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) assignTag(r *http.Request) (int, interface{}, error) {
+	t, err := s.tag(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	key := assignmentKey(fmt.Sprint(t.TagID), r.PathValue("resourceType"), r.PathValue("resourceId"))
+	if s.assignments[key] {
+		return 0, nil, &apiError{http.StatusConflict, "tag is already assigned to the resource"}
+	}
+	s.assignments[key] = true
+
+	return http.StatusCreated, single(r, map[string]interface{}{
+		"tenantId":     t.TenantID,
+		"customerId":   t.CustomerID,
+		"tagId":        t.TagID,
+		"tagName":      t.Name,
+		"resourceType": r.PathValue("resourceType"),
+		"resourceId":   r.PathValue("resourceId"),
+	}), nil
+}
+
+func (s *Server) unassignTag(r *http.Request) (int, interface{}, error) {
+	t, err := s.tag(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	key := assignmentKey(fmt.Sprint(t.TagID), r.PathValue("resourceType"), r.PathValue("resourceId"))
+	if !s.assignments[key] {
+		return 0, nil, notFound("TagAssignment", "resourceId", r.PathValue("resourceId"))
+	}
+	delete(s.assignments, key)
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,278 @@
+package contabotest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/user"
+)
+
+// AddUser stores a user, filling in the ID and dates if unset
+func (s *Server) AddUser(u user.User) user.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fillUser(&u)
+	s.users.add(u)
+	return u
+}
+
+// AddRole stores a role, filling in the ID and dates if unset
+func (s *Server) AddRole(role user.Role) user.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillRole(&role)
+	s.roles.add(role)
+	return role
+}
+
+// fillUser sets the ID, owner and dates of a user
+func fillUser(u *user.User) {
+	if u.UserID == "" {
+		u.UserID = newUUID()
+	}
+	fillOwner(&u.TenantID, &u.CustomerID)
+	if u.Roles == nil {
+		u.Roles = []user.Role{}
+	}
+	if u.CreatedDate.IsZero() {
+		u.CreatedDate = now()
+	}
+	if u.UpdatedDate.IsZero() {
+		u.UpdatedDate = u.CreatedDate
+	}
+}
+
+// fillRole sets the ID, owner and dates of a role
+func (s *Server) fillRole(r *user.Role) {
+	if r.RoleID == 0 {
+		r.RoleID = s.newID()
+	}
+	fillOwner(&r.TenantID, &r.CustomerID)
+	if r.Type == "" {
+		r.Type = "apiPermission"
+	}
+	if r.CreatedDate.IsZero() {
+		r.CreatedDate = now()
+	}
+	if r.UpdatedDate.IsZero() {
+		r.UpdatedDate = r.CreatedDate
+	}
+}
+
+// registerUser adds the user and role routes
+func (s *Server) registerUser(mux *http.ServeMux) {
+	s.handle(mux, "GET /v1/users", s.listUsers)
+	s.handle(mux, "POST /v1/users", s.createUser)
+	s.handle(mux, "GET /v1/users/{userId}", s.getUser)
+	s.handle(mux, "PATCH /v1/users/{userId}", s.updateUser)
+	s.handle(mux, "DELETE /v1/users/{userId}", s.deleteUser)
+	s.handle(mux, "GET /v1/roles", s.listRoles)
+	s.handle(mux, "POST /v1/roles", s.createRole)
+	s.handle(mux, "GET /v1/roles/{roleId}", s.getRole)
+	s.handle(mux, "PATCH /v1/roles/{roleId}", s.updateRole)
+	s.handle(mux, "DELETE /v1/roles/{roleId}", s.deleteRole)
+}
+
+// user returns the user of the request path
+func (s *Server) user(r *http.Request) (*user.User, error) {
+	id := r.PathValue("userId")
+	u, ok := s.users.get(id)
+	if !ok {
+		return nil, notFound("User", "userId", id)
+	}
+	return u, nil
+}
+
+// resolveRoles returns the roles with the given IDs
+func (s *Server) resolveRoles(ids []int64) ([]user.Role, error) {
+	roles := make([]user.Role, 0, len(ids))
+	for _, id := range ids {
+		role, ok := s.roles.get(fmt.Sprint(id))
+		if !ok {
+			return nil, badRequest("role %d does not exist", id)
+		}
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (s *Server) listUsers(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.users.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createUser(r *http.Request) (int, interface{}, error) {
+	var req user.CreateUserRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if !strings.Contains(req.Email, "@") {
+		return 0, nil, badRequest("email must be an email")
+	}
+	for _, u := range s.users.items {
+		if strings.EqualFold(u.Email, req.Email) {
+			return 0, nil, &apiError{http.StatusConflict, fmt.Sprintf("user with email %s already exists", req.Email)}
+		}
+	}
+	roles, err := s.resolveRoles(req.Roles)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	u := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Enabled:   req.Enabled,
+		Admin:     req.Admin,
+		Roles:     roles,
+	}
+	fillUser(&u)
+	s.users.add(u)
+
+	return http.StatusCreated, single(r, u), nil
+}
+
+func (s *Server) getUser(r *http.Request) (int, interface{}, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, u), nil
+}
+
+func (s *Server) updateUser(r *http.Request) (int, interface{}, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req user.PatchUserRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.FirstName != nil {
+		u.FirstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		u.LastName = *req.LastName
+	}
+	if req.Email != nil {
+		u.Email = *req.Email
+		u.EmailVerified = false
+	}
+	if req.Enabled != nil {
+		u.Enabled = *req.Enabled
+	}
+	if req.Admin != nil {
+		u.Admin = *req.Admin
+	}
+	if req.Roles != nil {
+		roles, err := s.resolveRoles(req.Roles)
+		if err != nil {
+			return 0, nil, err
+		}
+		u.Roles = roles
+	}
+	u.UpdatedDate = now()
+	return http.StatusOK, single(r, u), nil
+}
+
+func (s *Server) deleteUser(r *http.Request) (int, interface{}, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	s.users.remove(u.UserID)
+	return http.StatusNoContent, nil, nil
+}
+
+// role returns the role of the request path
+func (s *Server) role(r *http.Request) (*user.Role, error) {
+	id, err := parseID(r, "roleId")
+	if err != nil {
+		return nil, err
+	}
+	role, ok := s.roles.get(fmt.Sprint(id))
+	if !ok {
+		return nil, notFound("Role", "roleId", fmt.Sprint(id))
+	}
+	return role, nil
+}
+
+func (s *Server) listRoles(r *http.Request) (int, interface{}, error) {
+	page, err := paginate(r, s.roles.items)
+	return http.StatusOK, page, err
+}
+
+func (s *Server) createRole(r *http.Request) (int, interface{}, error) {
+	var req user.CreateRoleRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name == "" {
+		return 0, nil, badRequest("name must not be empty")
+	}
+	if req.Type != "" && !req.Type.IsValid() {
+		return 0, nil, badRequest("type must be one of apiPermission, resourcePermission")
+	}
+
+	role := user.Role{
+		Name:               req.Name,
+		Admin:              req.Admin,
+		AccessAllResources: req.AccessAllResources,
+		Type:               req.Type,
+	}
+	s.fillRole(&role)
+	s.roles.add(role)
+
+	return http.StatusCreated, single(r, role), nil
+}
+
+func (s *Server) getRole(r *http.Request) (int, interface{}, error) {
+	role, err := s.role(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, single(r, role), nil
+}
+
+func (s *Server) updateRole(r *http.Request) (int, interface{}, error) {
+	role, err := s.role(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req user.PatchRoleRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Name != nil {
+		role.Name = *req.Name
+	}
+	if req.Admin != nil {
+		role.Admin = *req.Admin
+	}
+	if req.AccessAllResources != nil {
+		role.AccessAllResources = *req.AccessAllResources
+	}
+	role.UpdatedDate = now()
+	return http.StatusOK, single(r, role), nil
+}
+
+func (s *Server) deleteRole(r *http.Request) (int, interface{}, error) {
+	role, err := s.role(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i := range s.users.items {
+		for _, assigned := range s.users.items[i].Roles {
+			if assigned.RoleID == role.RoleID {
+				return 0, nil, &apiError{http.StatusConflict, fmt.Sprintf("role %d is assigned to users", role.RoleID)}
+			}
+		}
+	}
+	s.roles.remove(fmt.Sprint(role.RoleID))
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,70 @@
+package contabo
+
+import "context"
+
+// contextKey is the type of the context keys defined by this package
+type contextKey int
+
+const (
+	traceIDKey contextKey = iota
+	requestIDKey
+	traceContextKey
+	responseHookKey
+)
+
+// WithTraceID returns a context whose API requests carry the given x-trace-id,
+// grouping them in Contabo's audit logs
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceIDFromContext returns the x-trace-id set with WithTraceID. A value
+// stored under the bare string key "x-trace-id", as documented before
+// WithTraceID existed, is still honoured; that key is deprecated and will be
+// ignored from the next release.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDKey).(string)
+	if !ok || traceID == "" {
+		traceID, ok = ctx.Value("x-trace-id").(string)
+	}
+	return traceID, ok && traceID != ""
+}
+
+// WithRequestID returns a context whose API requests use the given
+// x-request-id instead of a generated one
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the x-request-id set with WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok && requestID != ""
+}
+
+// TraceContext is a W3C trace context (https://www.w3.org/TR/trace-context/)
+type TraceContext struct {
+	TraceParent string
+	TraceState  string
+}
+
+// TraceID returns the trace-id field of the traceparent, or "" if it is malformed
+func (tc TraceContext) TraceID() string {
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	if len(tc.TraceParent) < 55 || tc.TraceParent[2] != '-' || tc.TraceParent[35] != '-' {
+		return ""
+	}
+	return tc.TraceParent[3:35]
+}
+
+// WithTraceContext returns a context whose API requests propagate the given
+// W3C trace context, e.g. one received by an inbound HTTP handler
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey, tc)
+}
+
+// TraceContextFromContext returns the trace context set with WithTraceContext
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok && tc.TraceParent != ""
+}
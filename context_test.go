@@ -0,0 +1,35 @@
+package contabo
+
+import (
+	"context"
+	"testing"
+)
+
+type otherKey string
+
+func TestTraceIDFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}{
+		{"unset", context.Background(), "", false},
+		{"set", WithTraceID(context.Background(), "trace-1"), "trace-1", true},
+		{"empty", WithTraceID(context.Background(), ""), "", false},
+		{"innermost wins", WithTraceID(WithTraceID(context.Background(), "outer"), "inner"), "inner", true},
+		{"deprecated bare string key", context.WithValue(context.Background(), "x-trace-id", "legacy"), "legacy", true},
+		{"WithTraceID preferred over bare string key", WithTraceID(context.WithValue(context.Background(), "x-trace-id", "legacy"), "trace-1"), "trace-1", true},
+		{"empty bare string key", context.WithValue(context.Background(), "x-trace-id", ""), "", false},
+		{"other package key ignored", context.WithValue(context.Background(), otherKey("x-trace-id"), "foreign"), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TraceIDFromContext(tt.ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("TraceIDFromContext() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package dns
+
+// RecordType is the type of a DNS record. Types the SDK does not know are
+// decoded and preserved unchanged; IsValid reports whether a type is known.
+type RecordType string
+
+// Known record types
+const (
+	RecordTypeA     RecordType = "A"
+	RecordTypeAAAA  RecordType = "AAAA"
+	RecordTypeCNAME RecordType = "CNAME"
+	RecordTypeMX    RecordType = "MX"
+	RecordTypeTXT   RecordType = "TXT"
+	RecordTypeSRV   RecordType = "SRV"
+	RecordTypeNS    RecordType = "NS"
+	RecordTypeCAA   RecordType = "CAA"
+	RecordTypePTR   RecordType = "PTR"
+)
+
+// IsValid reports whether t is a known record type
+func (t RecordType) IsValid() bool {
+	switch t {
+	case RecordTypeA, RecordTypeAAAA, RecordTypeCNAME, RecordTypeMX, RecordTypeTXT,
+		RecordTypeSRV, RecordTypeNS, RecordTypeCAA, RecordTypePTR:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package dns
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllZones returns an iterator over the DNS zones of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllZones(ctx context.Context, opts *ListOptions) iter.Seq2[Zone, error] {
+	return paginate.All(ctx, listFields.StartPage(opts), s.zonePages(opts), zoneKey)
+}
+
+// ListAllZones retrieves the DNS zones of all pages, fetching up to
+// opts.Concurrency pages at once. On error the DNS zones read so far are
+// returned with it.
+func (s *Service) ListAllZones(ctx context.Context, opts *ListOptions) ([]Zone, error) {
+	return paginate.List(ctx, listFields.StartPage(opts), listFields.Concurrency(opts), s.zonePages(opts), zoneKey)
+}
+
+// zonePages returns a function fetching a page of DNS zones
+func (s *Service) zonePages(opts *ListOptions) paginate.Fetch[Zone] {
+	return func(ctx context.Context, page int) (*paginate.Page[Zone], error) {
+		resp, err := s.ListZones(ctx, listFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Zone]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// zoneKey identifies a zone for de-duplication
+func zoneKey(z Zone) string {
+	return z.ZoneID
+}
+
+// AllRecords returns an iterator over the records of a zone of all pages
+func (s *Service) AllRecords(ctx context.Context, zoneName string, opts *ListRecordsOptions) iter.Seq2[Record, error] {
+	return paginate.All(ctx, recordFields.StartPage(opts), s.recordPages(zoneName, opts), recordKey)
+}
+
+// ListAllRecords retrieves the records of a zone of all pages
+func (s *Service) ListAllRecords(ctx context.Context, zoneName string, opts *ListRecordsOptions) ([]Record, error) {
+	return paginate.List(ctx, recordFields.StartPage(opts), recordFields.Concurrency(opts), s.recordPages(zoneName, opts), recordKey)
+}
+
+// recordPages returns a function fetching a page of records of a zone
+func (s *Service) recordPages(zoneName string, opts *ListRecordsOptions) paginate.Fetch[Record] {
+	return func(ctx context.Context, page int) (*paginate.Page[Record], error) {
+		resp, err := s.ListRecords(ctx, zoneName, recordFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Record]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// recordKey identifies a record for de-duplication
+func recordKey(r Record) string {
+	return r.RecordID
+}
+
+// AllPTRRecords returns an iterator over the reverse DNS entries of all pages
+func (s *Service) AllPTRRecords(ctx context.Context, opts *ListOptions) iter.Seq2[PTRRecord, error] {
+	return paginate.All(ctx, listFields.StartPage(opts), s.ptrRecordPages(opts), ptrRecordKey)
+}
+
+// ListAllPTRRecords retrieves the reverse DNS entries of all pages
+func (s *Service) ListAllPTRRecords(ctx context.Context, opts *ListOptions) ([]PTRRecord, error) {
+	return paginate.List(ctx, listFields.StartPage(opts), listFields.Concurrency(opts), s.ptrRecordPages(opts), ptrRecordKey)
+}
+
+// ptrRecordPages returns a function fetching a page of reverse DNS entries
+func (s *Service) ptrRecordPages(opts *ListOptions) paginate.Fetch[PTRRecord] {
+	return func(ctx context.Context, page int) (*paginate.Page[PTRRecord], error) {
+		resp, err := s.ListPTRRecords(ctx, listFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[PTRRecord]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// ptrRecordKey identifies a reverse DNS entry for de-duplication
+func ptrRecordKey(p PTRRecord) string {
+	return p.IPAddress
+}
+
+// Pagination fields of the list options, for internal/paginate
+var (
+	listFields   paginate.Fields[ListOptions]        = func(o *ListOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+	recordFields paginate.Fields[ListRecordsOptions] = func(o *ListRecordsOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+)
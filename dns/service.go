@@ -3,6 +3,9 @@ package dns
 import (
 	"context"
 	"fmt"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +22,24 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListRecordsOptions filters the records returned by ListRecords.
+// Empty fields are not sent.
+type ListRecordsOptions struct {
+	ListOptions
+
+	Name string
+	Type RecordType
+}
+
+// params returns the filter query parameters
+func (o *ListRecordsOptions) params() map[string]string {
+	return map[string]string{"name": o.Name, "type": string(o.Type)}
 }
 
 // Service handles DNS-related API operations
@@ -50,7 +71,10 @@ func (s *Service) ListZones(ctx context.Context, opts *ListOptions) (*ZonesRespo
 
 // GetZone retrieves a specific DNS zone by name
 func (s *Service) GetZone(ctx context.Context, zoneName string) (*Zone, error) {
-	path := fmt.Sprintf("/v1/dns/zones/%s", zoneName)
+	path, err := urlbuild.Path("/v1/dns/zones/%s", zoneName)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Zone `json:"data"`
@@ -60,7 +84,7 @@ func (s *Service) GetZone(ctx context.Context, zoneName string) (*Zone, error) {
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("zone not found")
+		return nil, fmt.Errorf("zone %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -84,17 +108,23 @@ func (s *Service) CreateZone(ctx context.Context, req *CreateZoneRequest) (*Zone
 
 // DeleteZone deletes a DNS zone
 func (s *Service) DeleteZone(ctx context.Context, zoneName string) error {
-	path := fmt.Sprintf("/v1/dns/zones/%s", zoneName)
+	path, err := urlbuild.Path("/v1/dns/zones/%s", zoneName)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // Records
 
 // ListRecords retrieves DNS records for a zone
-func (s *Service) ListRecords(ctx context.Context, zoneName string, opts *ListOptions) (*RecordsResponse, error) {
-	path := fmt.Sprintf("/v1/dns/zones/%s/records", zoneName)
+func (s *Service) ListRecords(ctx context.Context, zoneName string, opts *ListRecordsOptions) (*RecordsResponse, error) {
+	path, err := urlbuild.Path("/v1/dns/zones/%s/records", zoneName)
+	if err != nil {
+		return nil, err
+	}
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp RecordsResponse
@@ -107,7 +137,10 @@ func (s *Service) ListRecords(ctx context.Context, zoneName string, opts *ListOp
 
 // GetRecord retrieves a specific DNS record
 func (s *Service) GetRecord(ctx context.Context, zoneName, recordID string) (*Record, error) {
-	path := fmt.Sprintf("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	path, err := urlbuild.Path("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Record `json:"data"`
@@ -117,7 +150,7 @@ func (s *Service) GetRecord(ctx context.Context, zoneName, recordID string) (*Re
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("record not found")
+		return nil, fmt.Errorf("record %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -125,7 +158,10 @@ func (s *Service) GetRecord(ctx context.Context, zoneName, recordID string) (*Re
 
 // CreateRecord creates a new DNS record
 func (s *Service) CreateRecord(ctx context.Context, zoneName string, req *CreateRecordRequest) (*Record, error) {
-	path := fmt.Sprintf("/v1/dns/zones/%s/records", zoneName)
+	path, err := urlbuild.Path("/v1/dns/zones/%s/records", zoneName)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp CreateRecordResponse
 	if err := s.client.Post(ctx, path, req, &resp); err != nil {
@@ -141,7 +177,10 @@ func (s *Service) CreateRecord(ctx context.Context, zoneName string, req *Create
 
 // UpdateRecord updates a DNS record
 func (s *Service) UpdateRecord(ctx context.Context, zoneName, recordID string, req *PatchRecordRequest) (*Record, error) {
-	path := fmt.Sprintf("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	path, err := urlbuild.Path("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Record `json:"data"`
@@ -159,7 +198,10 @@ func (s *Service) UpdateRecord(ctx context.Context, zoneName, recordID string, r
 
 // DeleteRecord deletes a DNS record
 func (s *Service) DeleteRecord(ctx context.Context, zoneName, recordID string) error {
-	path := fmt.Sprintf("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	path, err := urlbuild.Path("/v1/dns/zones/%s/records/%s", zoneName, recordID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
@@ -182,7 +224,10 @@ func (s *Service) ListPTRRecords(ctx context.Context, opts *ListOptions) (*PTRRe
 
 // GetPTRRecord retrieves a specific PTR record by IP address
 func (s *Service) GetPTRRecord(ctx context.Context, ipAddress string) (*PTRRecord, error) {
-	path := fmt.Sprintf("/v1/dns/ptrs/%s", ipAddress)
+	path, err := urlbuild.Path("/v1/dns/ptrs/%s", ipAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []PTRRecord `json:"data"`
@@ -192,7 +237,7 @@ func (s *Service) GetPTRRecord(ctx context.Context, ipAddress string) (*PTRRecor
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("PTR record not found")
+		return nil, fmt.Errorf("PTR record %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -200,7 +245,10 @@ func (s *Service) GetPTRRecord(ctx context.Context, ipAddress string) (*PTRRecor
 
 // UpdatePTRRecord updates a PTR record
 func (s *Service) UpdatePTRRecord(ctx context.Context, ipAddress string, req *PatchPTRRequest) (*PTRRecord, error) {
-	path := fmt.Sprintf("/v1/dns/ptrs/%s", ipAddress)
+	path, err := urlbuild.Path("/v1/dns/ptrs/%s", ipAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []PTRRecord `json:"data"`
@@ -218,41 +266,8 @@ func (s *Service) UpdatePTRRecord(ctx context.Context, ipAddress string, req *Pa
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
-	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
-	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
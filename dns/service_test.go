@@ -0,0 +1,62 @@
+package dns_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/dns"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListRecordsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *dns.ListRecordsOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"empty options", &dns.ListRecordsOptions{}, ""},
+		{"name", &dns.ListRecordsOptions{Name: "www"}, "name=www"},
+		{"typed record type", &dns.ListRecordsOptions{Type: dns.RecordTypeAAAA}, "type=AAAA"},
+		{"all filters", &dns.ListRecordsOptions{ListOptions: dns.ListOptions{Page: 2, Size: 5}, Name: "mail", Type: dns.RecordTypeMX}, "name=mail&page=2&size=5&type=MX"},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, server := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+	server.AddZone(dns.Zone{Name: "example.com"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.DNS.ListRecords(context.Background(), "example.com", tt.opts); err != nil {
+				t.Fatalf("ListRecords() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
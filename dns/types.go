@@ -42,14 +42,14 @@ type CreateZoneResponse struct {
 
 // Record represents a DNS record
 type Record struct {
-	RecordID string `json:"recordId"`
-	TenantID string `json:"tenantId"`
-	CustomerID string `json:"customerId"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Content  string `json:"content"`
-	TTL      int    `json:"ttl"`
-	Priority *int   `json:"priority,omitempty"`
+	RecordID   string     `json:"recordId"`
+	TenantID   string     `json:"tenantId"`
+	CustomerID string     `json:"customerId"`
+	Name       string     `json:"name"`
+	Type       RecordType `json:"type"`
+	Content    string     `json:"content"`
+	TTL        int        `json:"ttl"`
+	Priority   *int       `json:"priority,omitempty"`
 }
 
 // RecordsResponse represents the response for listing DNS records
@@ -72,11 +72,11 @@ type RecordsResponse struct {
 
 // CreateRecordRequest represents the request body for creating a DNS record
 type CreateRecordRequest struct {
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Content  string `json:"content"`
-	TTL      int    `json:"ttl"`
-	Priority *int   `json:"priority,omitempty"`
+	Name     string     `json:"name"`
+	Type     RecordType `json:"type"`
+	Content  string     `json:"content"`
+	TTL      int        `json:"ttl"`
+	Priority *int       `json:"priority,omitempty"`
 }
 
 // CreateRecordResponse represents the response when creating a DNS record
@@ -86,19 +86,19 @@ type CreateRecordResponse struct {
 
 // PatchRecordRequest represents the request body for updating a DNS record
 type PatchRecordRequest struct {
-	Name     *string `json:"name,omitempty"`
-	Type     *string `json:"type,omitempty"`
-	Content  *string `json:"content,omitempty"`
-	TTL      *int    `json:"ttl,omitempty"`
-	Priority *int    `json:"priority,omitempty"`
+	Name     *string     `json:"name,omitempty"`
+	Type     *RecordType `json:"type,omitempty"`
+	Content  *string     `json:"content,omitempty"`
+	TTL      *int        `json:"ttl,omitempty"`
+	Priority *int        `json:"priority,omitempty"`
 }
 
 // PTRRecord represents a PTR (reverse DNS) record
 type PTRRecord struct {
-	IPAddress string `json:"ipAddress"`
-	TenantID  string `json:"tenantId"`
+	IPAddress  string `json:"ipAddress"`
+	TenantID   string `json:"tenantId"`
 	CustomerID string `json:"customerId"`
-	PTR       string `json:"ptr"`
+	PTR        string `json:"ptr"`
 }
 
 // PTRRecordsResponse represents the response for listing PTR records
@@ -0,0 +1,191 @@
+package contabo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PlannedCall is a mutating API call recorded in dry-run mode
+type PlannedCall struct {
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	PathTemplate string          `json:"pathTemplate"`
+	Service      string          `json:"service,omitempty"`
+	Operation    string          `json:"operation,omitempty"`
+	Body         json.RawMessage `json:"body,omitempty"` // Request body with credentials and secret values redacted
+	RequestID    string          `json:"requestId"`
+	Time         time.Time       `json:"time"`
+}
+
+// Plan collects the mutating calls a client would have made in dry-run mode.
+// It is safe for concurrent use.
+type Plan struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+}
+
+// NewPlan creates an empty plan
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Calls returns the recorded calls in the order they were made
+func (p *Plan) Calls() []PlannedCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PlannedCall(nil), p.calls...)
+}
+
+// Len returns the number of recorded calls
+func (p *Plan) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.calls)
+}
+
+// Reset removes all recorded calls
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls = nil
+}
+
+// record appends a call to the plan
+func (p *Plan) record(call PlannedCall) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls = append(p.calls, call)
+}
+
+// MarshalJSON encodes the plan as {"calls": [...]}
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	calls := p.Calls()
+	if calls == nil {
+		calls = []PlannedCall{}
+	}
+	return json.Marshal(struct {
+		Calls []PlannedCall `json:"calls"`
+	}{calls})
+}
+
+// WriteJSON writes the plan as indented JSON
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// WriteText writes the plan in a human-readable form
+func (p *Plan) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, p.String())
+	return err
+}
+
+// String returns the plan in a human-readable form
+func (p *Plan) String() string {
+	calls := p.Calls()
+
+	var b strings.Builder
+	switch len(calls) {
+	case 0:
+		b.WriteString("Dry-run plan: no mutating calls\n")
+	case 1:
+		b.WriteString("Dry-run plan: 1 mutating call\n")
+	default:
+		fmt.Fprintf(&b, "Dry-run plan: %d mutating calls\n", len(calls))
+	}
+
+	for i, call := range calls {
+		fmt.Fprintf(&b, "\n%d. %s %s", i+1, call.Method, call.Path)
+		if call.Operation != "" {
+			fmt.Fprintf(&b, " (%s %s)", call.Service, call.Operation)
+		}
+		b.WriteString("\n")
+
+		if len(call.Body) > 0 {
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, call.Body, "   ", "  "); err == nil {
+				b.WriteString("   ")
+				b.Write(indented.Bytes())
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// dryRun returns the middleware that records mutating calls into plan and
+// answers them with a synthetic success; other calls are passed to next
+func (c *Client) dryRun(plan *Plan) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*http.Response, error) {
+			if !isMutating(call.Method) {
+				return next(ctx, call)
+			}
+
+			planned := PlannedCall{
+				Method:       call.Method,
+				Path:         call.Path,
+				PathTemplate: call.PathTemplate,
+				Service:      call.Service,
+				Operation:    call.Operation,
+				Time:         time.Now(),
+			}
+			planned.RequestID, _ = RequestIDFromContext(ctx)
+
+			var body []byte
+			if call.Body != nil {
+				var err error
+				body, err = json.Marshal(call.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal request body: %w", err)
+				}
+				planned.Body = json.RawMessage(redactJSON(body))
+			}
+			plan.record(planned)
+
+			c.logger.LogAttrs(ctx, slog.LevelInfo, "contabo: dry run, call not sent",
+				slog.String("method", call.Method),
+				slog.String("path", call.Path),
+				slog.String("request_id", planned.RequestID),
+			)
+
+			return dryRunResponse(call, body), nil
+		}
+	}
+}
+
+// dryRunResponse builds the synthetic success of a recorded call. The result
+// is decoded from {"data": [body]}, so that service methods expecting the
+// created or updated resource get one carrying the requested values.
+func dryRunResponse(call *Call, body []byte) *http.Response {
+	if call.Result != nil {
+		if len(body) == 0 || body[0] != '{' || json.Unmarshal([]byte(`{"data":[`+string(body)+`]}`), call.Result) != nil {
+			_ = json.Unmarshal([]byte(`{"data":[{}]}`), call.Result)
+		}
+	}
+
+	header := make(http.Header)
+	header.Set("X-Dry-Run", "true")
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       http.NoBody,
+	}
+}
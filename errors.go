@@ -1,26 +1,58 @@
 package contabo
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
 )
 
 // Common errors
 var (
-	ErrMissingClientID     = errors.New("client ID is required")
-	ErrMissingClientSecret = errors.New("client secret is required")
-	ErrMissingUsername     = errors.New("username (API user email) is required")
-	ErrMissingPassword     = errors.New("password (API password) is required")
+	ErrMissingClientID      = errors.New("client ID is required")
+	ErrMissingClientSecret  = errors.New("client secret is required")
+	ErrMissingUsername      = errors.New("username (API user email) is required")
+	ErrMissingPassword      = errors.New("password (API password) is required")
 	ErrAuthenticationFailed = errors.New("authentication failed")
-	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrInvalidToken         = errors.New("invalid or expired token")
+	ErrInvalidConfig        = errors.New("invalid configuration")
+)
+
+// API error sentinels, matched with errors.Is against errors returned by the SDK
+var (
+	ErrNotFound     = apierr.ErrNotFound
+	ErrConflict     = apierr.ErrConflict
+	ErrRateLimited  = apierr.ErrRateLimited
+	ErrValidation   = apierr.ErrValidation
+	ErrUnauthorized = apierr.ErrUnauthorized
+	ErrForbidden    = apierr.ErrForbidden
+	ErrServer       = apierr.ErrServer
 )
 
+// PageError reports the failure to fetch one page in a ListAll call that
+// fetches pages concurrently
+type PageError = apierr.PageError
+
+// FieldError describes a validation failure of a single request field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
 // APIError represents an error returned by the Contabo API
 type APIError struct {
-	StatusCode int
-	Message    string
-	TraceID    string
-	RequestID  string
+	StatusCode  int
+	Message     string       // Message from the error body, or the raw body if it is not JSON
+	ErrorType   string       // Short error name such as "Bad Request", if provided
+	Details     []string     // Individual messages when the API returns several
+	FieldErrors []FieldError // Per-field validation errors
+	Body        string       // Raw response body
+	TraceID     string
+	RequestID   string
 }
 
 // Error implements the error interface
@@ -28,12 +60,119 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
 }
 
-// NewAPIError creates a new APIError
-func NewAPIError(statusCode int, message, requestID, traceID string) *APIError {
-	return &APIError{
+// Is reports whether the error matches one of the API error sentinels
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// NewAPIError creates a new APIError, decoding Contabo's error envelope from body
+func NewAPIError(statusCode int, body, requestID, traceID string) *APIError {
+	e := &APIError{
 		StatusCode: statusCode,
-		Message:    message,
+		Message:    body,
+		Body:       body,
 		RequestID:  requestID,
 		TraceID:    traceID,
 	}
+	e.decodeBody()
+	return e
+}
+
+// errorEnvelope is the JSON error body returned by the Contabo API
+type errorEnvelope struct {
+	StatusCode int             `json:"statusCode"`
+	Message    json.RawMessage `json:"message"`
+	Error      string          `json:"error"`
+	Errors     []struct {
+		Field       string            `json:"field"`
+		Property    string            `json:"property"`
+		Message     string            `json:"message"`
+		Constraints map[string]string `json:"constraints"`
+	} `json:"errors"`
+}
+
+// decodeBody fills the typed fields from the raw body, leaving Message as the
+// raw body if it is not a recognised envelope
+func (e *APIError) decodeBody() {
+	var env errorEnvelope
+	if err := json.Unmarshal([]byte(e.Body), &env); err != nil {
+		return
+	}
+
+	e.ErrorType = env.Error
+
+	// message is either a single string or a list of validation messages
+	var message string
+	if err := json.Unmarshal(env.Message, &message); err != nil {
+		var messages []string
+		if err := json.Unmarshal(env.Message, &messages); err == nil {
+			e.Details = messages
+			message = strings.Join(messages, "; ")
+		}
+	}
+
+	for _, fe := range env.Errors {
+		field := fe.Field
+		if field == "" {
+			field = fe.Property
+		}
+
+		if fe.Message != "" {
+			e.FieldErrors = append(e.FieldErrors, FieldError{Field: field, Message: fe.Message})
+			continue
+		}
+
+		// Constraint names are map keys; sort them for a stable order
+		names := make([]string, 0, len(fe.Constraints))
+		for name := range fe.Constraints {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			e.FieldErrors = append(e.FieldErrors, FieldError{Field: field, Message: fe.Constraints[name]})
+		}
+	}
+
+	switch {
+	case message != "":
+		e.Message = message
+	case env.Error != "":
+		e.Message = env.Error
+	}
+}
+
+// IsNotFound reports whether err is a 404 or a missing resource
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is a 409 Conflict
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is a 429 Too Many Requests
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsValidation reports whether err is a request validation failure
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
 }
@@ -0,0 +1,163 @@
+package contabo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   APIError // StatusCode, Body, RequestID and TraceID are filled in by the test
+	}{
+		{
+			name:   "string message",
+			status: 404,
+			body:   `{"statusCode":404,"message":"Entry Instances not found by instanceId: 1","error":"Not Found"}`,
+			want:   APIError{Message: "Entry Instances not found by instanceId: 1", ErrorType: "Not Found"},
+		},
+		{
+			name:   "array message",
+			status: 400,
+			body:   `{"statusCode":400,"message":["name must be shorter than 255 characters","size must be a number"],"error":"Bad Request"}`,
+			want: APIError{
+				Message:   "name must be shorter than 255 characters; size must be a number",
+				ErrorType: "Bad Request",
+				Details:   []string{"name must be shorter than 255 characters", "size must be a number"},
+			},
+		},
+		{
+			name:   "field errors",
+			status: 422,
+			body:   `{"statusCode":422,"message":"Validation failed","errors":[{"field":"displayName","message":"must not be empty"},{"property":"region","message":"unknown region"}]}`,
+			want: APIError{
+				Message: "Validation failed",
+				FieldErrors: []FieldError{
+					{Field: "displayName", Message: "must not be empty"},
+					{Field: "region", Message: "unknown region"},
+				},
+			},
+		},
+		{
+			name:   "constraints sorted by name",
+			status: 400,
+			body:   `{"statusCode":400,"error":"Bad Request","errors":[{"property":"name","constraints":{"maxLength":"name is too long","isNotEmpty":"name should not be empty"}}]}`,
+			want: APIError{
+				Message:   "Bad Request",
+				ErrorType: "Bad Request",
+				FieldErrors: []FieldError{
+					{Field: "name", Message: "name should not be empty"},
+					{Field: "name", Message: "name is too long"},
+				},
+			},
+		},
+		{
+			name:   "error name only",
+			status: 409,
+			body:   `{"statusCode":409,"error":"Conflict"}`,
+			want:   APIError{Message: "Conflict", ErrorType: "Conflict"},
+		},
+		{
+			name:   "unrecognised JSON",
+			status: 500,
+			body:   `{"detail":"something broke"}`,
+			want:   APIError{Message: `{"detail":"something broke"}`},
+		},
+		{
+			name:   "HTML body",
+			status: 502,
+			body:   "<html><body><h1>502 Bad Gateway</h1></body></html>",
+			want:   APIError{Message: "<html><body><h1>502 Bad Gateway</h1></body></html>"},
+		},
+		{
+			name:   "plain text body",
+			status: 429,
+			body:   "Too Many Requests",
+			want:   APIError{Message: "Too Many Requests"},
+		},
+		{
+			name:   "truncated JSON",
+			status: 400,
+			body:   `{"message":"name`,
+			want:   APIError{Message: `{"message":"name`},
+		},
+		{
+			name:   "empty body",
+			status: 503,
+			body:   "",
+			want:   APIError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAPIError(tt.status, tt.body, "request-1", "trace-1")
+
+			want := tt.want
+			want.StatusCode = tt.status
+			want.Body = tt.body
+			want.RequestID = "request-1"
+			want.TraceID = "trace-1"
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("NewAPIError() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{
+		apierr.ErrNotFound,
+		apierr.ErrConflict,
+		apierr.ErrRateLimited,
+		apierr.ErrValidation,
+		apierr.ErrUnauthorized,
+		apierr.ErrForbidden,
+		apierr.ErrServer,
+	}
+
+	tests := []struct {
+		status int
+		want   []error // Sentinels the error matches; all others must not match
+	}{
+		{400, []error{apierr.ErrValidation}},
+		{401, []error{apierr.ErrUnauthorized}},
+		{403, []error{apierr.ErrForbidden}},
+		{404, []error{apierr.ErrNotFound}},
+		{409, []error{apierr.ErrConflict}},
+		{422, []error{apierr.ErrValidation}},
+		{429, []error{apierr.ErrRateLimited}},
+		{500, []error{apierr.ErrServer}},
+		{503, []error{apierr.ErrServer}},
+		{418, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			// Matching must hold through wrapping, as service packages wrap API errors
+			err := fmt.Errorf("get instance: %w", NewAPIError(tt.status, `{"message":"failed"}`, "", ""))
+			for _, sentinel := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					want = want || w == sentinel
+				}
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(%d, %v) = %v, want %v", tt.status, sentinel, got, want)
+				}
+			}
+			if errors.Is(err, ErrInvalidToken) {
+				t.Errorf("errors.Is(%d, ErrInvalidToken) = true", tt.status)
+			}
+		})
+	}
+
+	if ErrNotFound != apierr.ErrNotFound || ErrServer != apierr.ErrServer {
+		t.Error("root sentinels are not the internal/apierr sentinels")
+	}
+}
@@ -4,20 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	contabo "github.com/mithucste30/contabo-api-golang"
 	"github.com/mithucste30/contabo-api-golang/compute"
 )
 
 func main() {
-	// Load credentials from environment variables
-	config := contabo.NewConfig(
-		os.Getenv("CONTABO_CLIENT_ID"),
-		os.Getenv("CONTABO_CLIENT_SECRET"),
-		os.Getenv("CONTABO_API_USER"),
-		os.Getenv("CONTABO_API_PASSWORD"),
-	)
+	// Load credentials from CONTABO_* environment variables or a profile
+	config, err := contabo.LoadConfig(nil)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
 
 	// Create SDK instance
 	sdk, err := contabo.NewSDK(config)
@@ -29,9 +26,8 @@ func main() {
 
 	// Example 1: List all compute instances
 	fmt.Println("=== Listing Compute Instances ===")
-	instances, err := sdk.Compute.ListInstances(ctx, &compute.ListOptions{
-		Page: 1,
-		Size: 10,
+	instances, err := sdk.Compute.ListInstances(ctx, &compute.ListInstancesOptions{
+		ListOptions: compute.ListOptions{Page: 1, Size: 10},
 	})
 	if err != nil {
 		log.Fatalf("Failed to list instances: %v", err)
@@ -46,10 +42,10 @@ func main() {
 	// Example 2: List all images
 	fmt.Println("\n=== Listing Images ===")
 	standardImage := true
-	images, err := sdk.Compute.ListImages(ctx, &compute.ListOptions{
-		Page: 1,
-		Size: 5,
-	}, &standardImage)
+	images, err := sdk.Compute.ListImages(ctx, &compute.ListImagesOptions{
+		ListOptions:   compute.ListOptions{Page: 1, Size: 5},
+		StandardImage: &standardImage,
+	})
 	if err != nil {
 		log.Fatalf("Failed to list images: %v", err)
 	}
@@ -0,0 +1,424 @@
+// Package faultinject provides an http.RoundTripper that injects failures
+// such as latency, dropped connections, rate limiting, server errors,
+// corrupted bodies and expired tokens into requests, for testing how code
+// using the SDK copes with API outages.
+package faultinject
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Kind identifies the type of a fault
+type Kind int
+
+const (
+	KindLatency Kind = iota
+	KindRefuseConnection
+	KindDropConnection
+	KindRateLimit
+	KindServerError
+	KindTruncatedBody
+	KindMalformedJSON
+	KindExpiredToken
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindLatency:
+		return "latency"
+	case KindRefuseConnection:
+		return "refuse-connection"
+	case KindDropConnection:
+		return "drop-connection"
+	case KindRateLimit:
+		return "rate-limit"
+	case KindServerError:
+		return "server-error"
+	case KindTruncatedBody:
+		return "truncated-body"
+	case KindMalformedJSON:
+		return "malformed-json"
+	case KindExpiredToken:
+		return "expired-token"
+	}
+	return "unknown"
+}
+
+// Fault describes a failure to inject. Create faults with the functions below.
+type Fault struct {
+	Kind       Kind
+	Latency    time.Duration // KindLatency
+	RetryAfter time.Duration // KindRateLimit, and KindServerError with status 503
+	StatusCode int           // KindServerError
+}
+
+// String describes the fault
+func (f Fault) String() string {
+	switch f.Kind {
+	case KindLatency:
+		return fmt.Sprintf("latency %s", f.Latency)
+	case KindRateLimit:
+		return fmt.Sprintf("429 retry after %s", f.RetryAfter)
+	case KindServerError:
+		return strconv.Itoa(f.StatusCode)
+	}
+	return f.Kind.String()
+}
+
+// Latency delays the request by d before sending it; other faults of later rules still apply
+func Latency(d time.Duration) Fault {
+	return Fault{Kind: KindLatency, Latency: d}
+}
+
+// RefuseConnection fails the request as if the connection could not be
+// established; the request never reaches the server
+func RefuseConnection() Fault {
+	return Fault{Kind: KindRefuseConnection}
+}
+
+// DropConnection sends the request, then fails it as if the connection was
+// reset before the response arrived, so the server may have processed it
+func DropConnection() Fault {
+	return Fault{Kind: KindDropConnection}
+}
+
+// RateLimited answers with 429 Too Many Requests and a Retry-After header
+// (omitted if retryAfter is 0) without sending the request
+func RateLimited(retryAfter time.Duration) Fault {
+	return Fault{Kind: KindRateLimit, RetryAfter: retryAfter}
+}
+
+// ServerError answers with the given 5xx status, e.g. 500, 502 or 503,
+// without sending the request
+func ServerError(statusCode int) Fault {
+	return Fault{Kind: KindServerError, StatusCode: statusCode}
+}
+
+// Unavailable answers with 503 Service Unavailable and a Retry-After header
+func Unavailable(retryAfter time.Duration) Fault {
+	return Fault{Kind: KindServerError, StatusCode: http.StatusServiceUnavailable, RetryAfter: retryAfter}
+}
+
+// TruncatedBody sends the request and cuts the response body off halfway,
+// failing the read with io.ErrUnexpectedEOF
+func TruncatedBody() Fault {
+	return Fault{Kind: KindTruncatedBody}
+}
+
+// MalformedJSON sends the request and replaces the response body with invalid JSON
+func MalformedJSON() Fault {
+	return Fault{Kind: KindMalformedJSON}
+}
+
+// ExpiredToken answers with 401 and an invalid_token error, as the API does
+// for an expired access token, without sending the request
+func ExpiredToken() Fault {
+	return Fault{Kind: KindExpiredToken}
+}
+
+// Rule injects a fault into matching requests.
+//
+// Which matching requests are affected is chosen by, in order: Schedule,
+// Times, Probability. Without any of them every matching request is affected.
+type Rule struct {
+	// HTTP method to match ("" matches any)
+	Method string
+
+	// Path pattern as in path.Match, e.g. "/v1/compute/instances/*"; a
+	// trailing "/**" matches any number of segments ("" matches any path)
+	Path string
+
+	Fault Fault
+
+	// 1-based numbers of the matching requests to affect, e.g. []int{1, 3}
+	Schedule []int
+
+	// Number of matching requests to affect, starting with the first
+	Times int
+
+	// Chance of affecting a matching request, between 0 and 1
+	Probability float64
+}
+
+// matches reports whether the rule applies to req
+func (r *Rule) matches(req *http.Request) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, req.Method) {
+		return false
+	}
+	switch {
+	case r.Path == "":
+		return true
+	case strings.HasSuffix(r.Path, "/**"):
+		prefix := strings.TrimSuffix(r.Path, "/**")
+		return req.URL.Path == prefix || strings.HasPrefix(req.URL.Path, prefix+"/")
+	}
+	ok, _ := path.Match(r.Path, req.URL.Path)
+	return ok
+}
+
+// triggers reports whether the n-th matching request is affected
+func (r *Rule) triggers(n int, rng *rand.Rand) bool {
+	switch {
+	case len(r.Schedule) > 0:
+		for _, s := range r.Schedule {
+			if s == n {
+				return true
+			}
+		}
+		return false
+	case r.Times > 0:
+		return n <= r.Times
+	case r.Probability > 0:
+		return rng.Float64() < r.Probability
+	}
+	return true
+}
+
+// Injection records a fault injected into a request
+type Injection struct {
+	Rule   int // Index of the rule in Transport.Rules
+	Method string
+	Path   string
+	Fault  Fault
+}
+
+// Transport is an http.RoundTripper injecting faults into requests before
+// passing them to Base. All rules are evaluated for every request; latency
+// faults accumulate and the first other fault that triggers decides the
+// outcome.
+// Set the fields before the first request.
+type Transport struct {
+	// Transport used for requests that are sent (defaults to http.DefaultTransport)
+	Base http.RoundTripper
+
+	Rules []Rule
+
+	// Seed of the random source used for Rule.Probability, for reproducible runs
+	// (a random seed is used if 0)
+	Seed uint64
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	counts    []int
+	injected  []Injection
+	initiated bool
+}
+
+// New creates a transport injecting faults according to rules
+func New(base http.RoundTripper, rules ...Rule) *Transport {
+	return &Transport{Base: base, Rules: rules}
+}
+
+// Injected returns the faults injected so far
+func (t *Transport) Injected() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Injection(nil), t.injected...)
+}
+
+// Reset clears the request counts and the injection log
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts = make([]int, len(t.Rules))
+	t.injected = nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency, fault := t.pick(req)
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+	if fault == nil {
+		return t.base().RoundTrip(req)
+	}
+
+	switch fault.Kind {
+	case KindRefuseConnection:
+		closeBody(req)
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Addr: addr(req), Err: syscall.ECONNREFUSED}
+
+	case KindDropConnection:
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, &net.OpError{Op: "read", Net: "tcp", Addr: addr(req), Err: syscall.ECONNRESET}
+
+	case KindRateLimit:
+		closeBody(req)
+		resp := response(req, http.StatusTooManyRequests, apiErrorBody(http.StatusTooManyRequests))
+		setRetryAfter(resp, fault.RetryAfter)
+		return resp, nil
+
+	case KindServerError:
+		closeBody(req)
+		resp := response(req, fault.StatusCode, apiErrorBody(fault.StatusCode))
+		setRetryAfter(resp, fault.RetryAfter)
+		return resp, nil
+
+	case KindExpiredToken:
+		closeBody(req)
+		resp := response(req, http.StatusUnauthorized, `{"error":"invalid_token","error_description":"Token is not active"}`)
+		resp.Header.Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="Token is not active"`)
+		return resp, nil
+
+	case KindTruncatedBody, KindMalformedJSON:
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		if fault.Kind == KindTruncatedBody {
+			resp.Body = &truncatedBody{r: bytes.NewReader(body[:len(body)/2])}
+		} else {
+			resp.Header.Set("Content-Type", "application/json")
+			resp.Body = io.NopCloser(strings.NewReader(`{"data":[{"tenantId":"DE",}`))
+		}
+		return resp, nil
+	}
+
+	return t.base().RoundTrip(req)
+}
+
+// pick evaluates the rules for req and returns the total latency and the
+// fault to inject, if any
+func (t *Transport) pick(req *http.Request) (time.Duration, *Fault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.initiated {
+		seed := t.Seed
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		t.rng = rand.New(rand.NewPCG(seed, seed))
+		t.counts = make([]int, len(t.Rules))
+		t.initiated = true
+	}
+
+	// Every matching rule counts the request, even once a fault has been
+	// chosen, so schedules refer to the same requests whatever the other rules do
+	var latency time.Duration
+	var fault *Fault
+	for i := range t.Rules {
+		rule := &t.Rules[i]
+		if !rule.matches(req) {
+			continue
+		}
+		t.counts[i]++
+		if !rule.triggers(t.counts[i], t.rng) || (fault != nil && rule.Fault.Kind != KindLatency) {
+			continue
+		}
+
+		t.injected = append(t.injected, Injection{Rule: i, Method: req.Method, Path: req.URL.Path, Fault: rule.Fault})
+		if rule.Fault.Kind == KindLatency {
+			latency += rule.Fault.Latency
+		} else {
+			fault = &rule.Fault
+		}
+	}
+	return latency, fault
+}
+
+// base returns the transport requests are sent with
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// truncatedBody returns the bytes of r, then io.ErrUnexpectedEOF
+type truncatedBody struct {
+	r *bytes.Reader
+}
+
+// Read implements io.Reader
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *truncatedBody) Close() error {
+	return nil
+}
+
+// response builds a synthetic JSON response to req
+func response(req *http.Request, statusCode int, body string) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if id := req.Header.Get("x-request-id"); id != "" {
+		header.Set("x-request-id", id)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// apiErrorBody returns Contabo's JSON error envelope for a status
+func apiErrorBody(statusCode int) string {
+	return fmt.Sprintf(`{"statusCode":%d,"message":%q}`, statusCode, http.StatusText(statusCode))
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds
+func setRetryAfter(resp *http.Response, d time.Duration) {
+	if d > 0 {
+		resp.Header.Set("Retry-After", strconv.Itoa(int((d+time.Second-1)/time.Second)))
+	}
+}
+
+// closeBody closes the body of a request that is not sent, as a transport must
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// addr returns the remote address of req for net errors
+func addr(req *http.Request) net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(req.URL.Hostname())}
+}
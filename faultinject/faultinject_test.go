@@ -0,0 +1,316 @@
+package faultinject_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mithucste30/contabo-api-golang/faultinject"
+)
+
+// newBackend starts a server answering every request with a small JSON body
+// and counting the requests that reached it
+func newBackend(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"data":[{"tenantId":"DE","customerId":"54321"}]}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// send makes a request through tr and returns its status, or 0 on error
+func send(t *testing.T, tr http.RoundTripper, method, url string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		return 0
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRuleTriggers(t *testing.T) {
+	tests := []struct {
+		name string
+		rule faultinject.Rule
+		want []int // 1-based numbers of the requests that fail
+	}{
+		{"every request", faultinject.Rule{}, []int{1, 2, 3, 4, 5, 6}},
+		{"times", faultinject.Rule{Times: 2}, []int{1, 2}},
+		{"times above the request count", faultinject.Rule{Times: 10}, []int{1, 2, 3, 4, 5, 6}},
+		{"schedule", faultinject.Rule{Schedule: []int{2, 5}}, []int{2, 5}},
+		{"schedule takes precedence over times", faultinject.Rule{Schedule: []int{3}, Times: 2}, []int{3}},
+		{"certain probability", faultinject.Rule{Probability: 1}, []int{1, 2, 3, 4, 5, 6}},
+		{"method", faultinject.Rule{Method: "post"}, []int{2, 4, 6}},
+		{"method and times", faultinject.Rule{Method: http.MethodPost, Times: 2}, []int{2, 4}},
+		{"path pattern", faultinject.Rule{Path: "/v1/compute/instances/*"}, []int{3, 4}},
+		{"path prefix", faultinject.Rule{Path: "/v1/compute/**"}, []int{1, 2, 3, 4, 5, 6}},
+		{"path prefix matches itself", faultinject.Rule{Path: "/v1/compute/instances/**"}, []int{1, 2, 3, 4, 5, 6}},
+		{"schedule counts matching requests only", faultinject.Rule{Path: "/v1/compute/instances/1/**", Schedule: []int{2, 3}}, []int{4, 5}},
+		{"no match", faultinject.Rule{Path: "/v1/dns/**"}, nil},
+	}
+
+	requests := []struct{ method, path string }{
+		{http.MethodGet, "/v1/compute/instances"},
+		{http.MethodPost, "/v1/compute/instances"},
+		{http.MethodGet, "/v1/compute/instances/1"},
+		{http.MethodPost, "/v1/compute/instances/1"},
+		{http.MethodGet, "/v1/compute/instances/1/snapshots"},
+		{http.MethodPost, "/v1/compute/instances/1/snapshots"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, hits := newBackend(t)
+			rule := tt.rule
+			rule.Fault = faultinject.ServerError(http.StatusBadGateway)
+			tr := faultinject.New(nil, rule)
+
+			var failed []int
+			for i, r := range requests {
+				if send(t, tr, r.method, backend.URL+r.path) == http.StatusBadGateway {
+					failed = append(failed, i+1)
+				}
+			}
+			if !slices.Equal(failed, tt.want) {
+				t.Errorf("failed requests %v, want %v", failed, tt.want)
+			}
+			if got, want := hits.Load(), int64(len(requests)-len(tt.want)); got != want {
+				t.Errorf("requests sent = %d, want %d", got, want)
+			}
+			if n := len(tr.Injected()); n != len(tt.want) {
+				t.Errorf("Injected() holds %d entries, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestProbabilitySeed(t *testing.T) {
+	backend, _ := newBackend(t)
+	pattern := func(seed uint64) []bool {
+		tr := faultinject.New(nil, faultinject.Rule{Fault: faultinject.ServerError(500), Probability: 0.5})
+		tr.Seed = seed
+		var failed []bool
+		for range 100 {
+			failed = append(failed, send(t, tr, http.MethodGet, backend.URL) == 500)
+		}
+		return failed
+	}
+
+	first := pattern(42)
+	if !slices.Equal(first, pattern(42)) {
+		t.Error("the same seed gave different faults")
+	}
+	n := 0
+	for _, failed := range first {
+		if failed {
+			n++
+		}
+	}
+	if n < 25 || n > 75 {
+		t.Errorf("%d of 100 requests failed with probability 0.5", n)
+	}
+}
+
+func TestRulesEvaluatedTogether(t *testing.T) {
+	backend, _ := newBackend(t)
+	tr := faultinject.New(nil,
+		faultinject.Rule{Fault: faultinject.Latency(time.Millisecond)},
+		faultinject.Rule{Fault: faultinject.ServerError(500), Schedule: []int{1}},
+		faultinject.Rule{Fault: faultinject.RateLimited(0), Times: 2},
+	)
+
+	// The first triggered fault decides; later rules still count the request
+	statuses := []int{
+		send(t, tr, http.MethodGet, backend.URL),
+		send(t, tr, http.MethodGet, backend.URL),
+		send(t, tr, http.MethodGet, backend.URL),
+	}
+	if want := []int{500, 429, 200}; !slices.Equal(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	var rules []int
+	for _, injection := range tr.Injected() {
+		rules = append(rules, injection.Rule)
+	}
+	if want := []int{0, 1, 0, 2, 0}; !slices.Equal(rules, want) {
+		t.Errorf("injected rules = %v, want %v", rules, want)
+	}
+
+	tr.Reset()
+	if len(tr.Injected()) != 0 {
+		t.Error("Injected() not empty after Reset")
+	}
+	if got := send(t, tr, http.MethodGet, backend.URL); got != 500 {
+		t.Errorf("status after Reset = %d, want the schedule to start over", got)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    faultinject.Fault
+		wantSent bool
+		check    func(t *testing.T, resp *http.Response, err error)
+	}{
+		{
+			name:  "refuse connection",
+			fault: faultinject.RefuseConnection(),
+			check: func(t *testing.T, _ *http.Response, err error) {
+				var opErr *net.OpError
+				if !errors.As(err, &opErr) || opErr.Op != "dial" {
+					t.Errorf("error = %v, want a dial error", err)
+				}
+			},
+		},
+		{
+			name:     "drop connection",
+			fault:    faultinject.DropConnection(),
+			wantSent: true,
+			check: func(t *testing.T, _ *http.Response, err error) {
+				var opErr *net.OpError
+				if !errors.As(err, &opErr) || opErr.Op != "read" {
+					t.Errorf("error = %v, want a read error", err)
+				}
+			},
+		},
+		{
+			name:  "rate limited",
+			fault: faultinject.RateLimited(1500 * time.Millisecond),
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil || resp.StatusCode != 429 || resp.Header.Get("Retry-After") != "2" {
+					t.Errorf("response = %v, %v, want 429 with Retry-After rounded up to 2", resp, err)
+				}
+			},
+		},
+		{
+			name:  "rate limited without Retry-After",
+			fault: faultinject.RateLimited(0),
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil || resp.StatusCode != 429 || resp.Header.Get("Retry-After") != "" {
+					t.Errorf("response = %v, %v, want 429 without Retry-After", resp, err)
+				}
+			},
+		},
+		{
+			name:  "unavailable",
+			fault: faultinject.Unavailable(3 * time.Second),
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil || resp.StatusCode != 503 || resp.Header.Get("Retry-After") != "3" {
+					t.Errorf("response = %v, %v, want 503 with Retry-After 3", resp, err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				if want := `{"statusCode":503,"message":"Service Unavailable"}`; string(body) != want {
+					t.Errorf("body = %s, want %s", body, want)
+				}
+			},
+		},
+		{
+			name:  "expired token",
+			fault: faultinject.ExpiredToken(),
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil || resp.StatusCode != 401 || !strings.Contains(resp.Header.Get("WWW-Authenticate"), "invalid_token") {
+					t.Errorf("response = %v, %v, want 401 invalid_token", resp, err)
+				}
+			},
+		},
+		{
+			name:     "truncated body",
+			fault:    faultinject.TruncatedBody(),
+			wantSent: true,
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, err := io.ReadAll(resp.Body)
+				if !errors.Is(err, io.ErrUnexpectedEOF) || len(body) == 0 || json.Valid(body) {
+					t.Errorf("body = %q, %v, want a partial body and io.ErrUnexpectedEOF", body, err)
+				}
+			},
+		},
+		{
+			name:     "malformed JSON",
+			fault:    faultinject.MalformedJSON(),
+			wantSent: true,
+			check: func(t *testing.T, resp *http.Response, err error) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, err := io.ReadAll(resp.Body)
+				if err != nil || resp.StatusCode != 200 || json.Valid(body) {
+					t.Errorf("body = %q, %v, want invalid JSON with status 200", body, err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, hits := newBackend(t)
+			tr := faultinject.New(nil, faultinject.Rule{Fault: tt.fault})
+
+			req, _ := http.NewRequest(http.MethodPost, backend.URL+"/v1/secrets", strings.NewReader(`{"name":"x"}`))
+			req.Header.Set("x-request-id", "req-1")
+			resp, err := tr.RoundTrip(req)
+			if resp != nil {
+				defer resp.Body.Close()
+				// Synthetic responses echo the request ID, as the API does
+				if !tt.wantSent && resp.Header.Get("x-request-id") != "req-1" {
+					t.Errorf("x-request-id = %q, want req-1", resp.Header.Get("x-request-id"))
+				}
+			}
+			tt.check(t, resp, err)
+
+			if sent := hits.Load() == 1; sent != tt.wantSent {
+				t.Errorf("request sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	backend, hits := newBackend(t)
+	tr := faultinject.New(nil,
+		faultinject.Rule{Fault: faultinject.Latency(20 * time.Millisecond)},
+		faultinject.Rule{Fault: faultinject.Latency(30 * time.Millisecond)},
+	)
+
+	start := time.Now()
+	if got := send(t, tr, http.MethodGet, backend.URL); got != 200 {
+		t.Fatalf("status = %d, want 200", got)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("request took %v, want the latencies added up to 50ms", elapsed)
+	}
+
+	// A cancelled request stops waiting and is not sent
+	tr = faultinject.New(nil, faultinject.Rule{Fault: faultinject.Latency(time.Hour)})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL, nil)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("requests sent = %d, want 1", n)
+	}
+}
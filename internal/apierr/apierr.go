@@ -0,0 +1,35 @@
+// Package apierr holds the error sentinels and types shared by the root package and
+// the service packages.
+package apierr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinels matched by APIError.Is and returned by the service packages
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrServer       = errors.New("server error")
+)
+
+// PageError reports the failure to fetch a page of a listing
+type PageError struct {
+	Page int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %v", e.Page, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,34 @@
+package paginate
+
+// Fields returns pointers to the page number and concurrency of a service's
+// list options struct O, usually the fields of the ListOptions it embeds
+type Fields[O any] func(opts *O) (page, concurrency *int)
+
+// StartPage returns the page to start iterating at; nil opts start at the first page
+func (f Fields[O]) StartPage(opts *O) int {
+	if opts == nil {
+		return 1
+	}
+	page, _ := f(opts)
+	return *page
+}
+
+// Concurrency returns the number of pages ListAll methods fetch at once
+func (f Fields[O]) Concurrency(opts *O) int {
+	if opts == nil {
+		return 1
+	}
+	_, concurrency := f(opts)
+	return *concurrency
+}
+
+// ForPage returns a copy of opts, which may be nil, for the given page
+func (f Fields[O]) ForPage(opts *O, page int) *O {
+	o := new(O)
+	if opts != nil {
+		*o = *opts
+	}
+	p, _ := f(o)
+	*p = page
+	return o
+}
@@ -0,0 +1,48 @@
+package paginate
+
+import "testing"
+
+// listOptions mirrors a service's ListOptions and an options struct embedding it
+type listOptions struct {
+	Page        int
+	Size        int
+	Concurrency int
+}
+
+type filterOptions struct {
+	listOptions
+	Name string
+}
+
+var filterFields Fields[filterOptions] = func(o *filterOptions) (page, concurrency *int) {
+	return &o.Page, &o.Concurrency
+}
+
+func TestFields(t *testing.T) {
+	opts := &filterOptions{listOptions: listOptions{Page: 3, Size: 20, Concurrency: 4}, Name: "web"}
+
+	if got := filterFields.StartPage(nil); got != 1 {
+		t.Errorf("StartPage(nil) = %d, want 1", got)
+	}
+	if got := filterFields.StartPage(opts); got != 3 {
+		t.Errorf("StartPage() = %d, want 3", got)
+	}
+	if got := filterFields.Concurrency(nil); got != 1 {
+		t.Errorf("Concurrency(nil) = %d, want 1", got)
+	}
+	if got := filterFields.Concurrency(opts); got != 4 {
+		t.Errorf("Concurrency() = %d, want 4", got)
+	}
+
+	page := filterFields.ForPage(opts, 7)
+	want := filterOptions{listOptions: listOptions{Page: 7, Size: 20, Concurrency: 4}, Name: "web"}
+	if *page != want {
+		t.Errorf("ForPage() = %+v, want %+v", *page, want)
+	}
+	if opts.Page != 3 {
+		t.Errorf("ForPage() changed the caller's options to page %d", opts.Page)
+	}
+	if got := filterFields.ForPage(nil, 2); *got != (filterOptions{listOptions: listOptions{Page: 2}}) {
+		t.Errorf("ForPage(nil) = %+v, want only Page set", *got)
+	}
+}
@@ -0,0 +1,198 @@
+// Package paginate walks the paginated list endpoints of the Contabo API.
+// It is shared by the service packages, which adapt their list responses to Page.
+package paginate
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+)
+
+// Page is one page of a listing
+type Page[T any] struct {
+	Items         []T
+	Size          int    // _pagination.size
+	TotalElements int64  // _pagination.totalElements
+	TotalPages    int    // _pagination.totalPages
+	Next          string // _links.next
+}
+
+// Fetch retrieves the page with the given 1-based number
+type Fetch[T any] func(ctx context.Context, page int) (*Page[T], error)
+
+// All returns an iterator over the items of all pages from page first on.
+//
+// The next page is taken from the next link if it names one, otherwise the
+// page number is incremented until TotalPages is reached. Items are
+// de-duplicated by key, so items moving to a later page because others were
+// added are not yielded twice. When TotalElements shrinks between pages, the
+// pages items may have moved back to are fetched again, so they are not
+// skipped. Iteration ends at the first error, which is yielded with the
+// zero value, or when ctx is done.
+func All[T any, K comparable](ctx context.Context, first int, fetch Fetch[T], key func(T) K) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if first < 1 {
+			first = 1
+		}
+
+		seen := make(map[K]struct{})
+		total := int64(-1)
+		for page := first; ; {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			p, err := fetch(ctx, page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// Items removed from earlier pages shift the remaining ones back,
+			// so step back to pick up those that moved past the boundary
+			if total >= 0 && p.TotalElements < total && page > first && p.Size > 0 {
+				back := int((total - p.TotalElements + int64(p.Size) - 1) / int64(p.Size))
+				total = p.TotalElements
+				page = max(first, page-back)
+				continue
+			}
+			total = p.TotalElements
+
+			for _, item := range p.Items {
+				k := key(item)
+				if _, ok := seen[k]; ok {
+					continue
+				}
+				seen[k] = struct{}{}
+				if err := ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next, ok := nextPage(p, page)
+			if !ok || len(p.Items) == 0 {
+				return
+			}
+			page = next
+		}
+	}
+}
+
+// Collect returns the items of seq, stopping at the first error. The items
+// read before the error are returned with it.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// List returns the items of all pages from page first on. With workers > 1
+// the first page is fetched alone and, once it reports TotalPages, the
+// remaining pages are fetched by that many concurrent workers and
+// reassembled in order; otherwise the pages are walked as by All.
+//
+// If pages fail, the items of the other pages are returned together with the
+// errors of the failed pages joined into one, each wrapped in an
+// *apierr.PageError.
+func List[T any, K comparable](ctx context.Context, first, workers int, fetch Fetch[T], key func(T) K) ([]T, error) {
+	if workers <= 1 {
+		return Collect(All(ctx, first, fetch, key))
+	}
+	if first < 1 {
+		first = 1
+	}
+
+	p, err := fetch(ctx, first)
+	if err != nil {
+		return nil, err
+	}
+	if p.TotalPages <= first || len(p.Items) == 0 {
+		return dedupe([][]T{p.Items}, key), nil
+	}
+
+	pages := make([][]T, p.TotalPages-first+1)
+	errs := make([]error, len(pages))
+	pages[0] = p.Items
+
+	numbers := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(pages)-1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				p, err := fetch(ctx, n)
+				if err != nil {
+					errs[n-first] = &apierr.PageError{Page: n, Err: err}
+					continue
+				}
+				pages[n-first] = p.Items
+			}
+		}()
+	}
+
+feed:
+	for n := first + 1; n <= p.TotalPages; n++ {
+		select {
+		case numbers <- n:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	// Once ctx is done, report it once rather than for every page
+	if err := ctx.Err(); err != nil {
+		return dedupe(pages, key), err
+	}
+	return dedupe(pages, key), errors.Join(errs...)
+}
+
+// dedupe concatenates pages, dropping items whose key was seen before
+func dedupe[T any, K comparable](pages [][]T, key func(T) K) []T {
+	var items []T
+	seen := make(map[K]struct{})
+	for _, page := range pages {
+		for _, item := range page {
+			k := key(item)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// nextPage returns the number of the page after p, which was fetched as page
+func nextPage[T any](p *Page[T], page int) (int, bool) {
+	if p.Next != "" {
+		if u, err := url.Parse(p.Next); err == nil {
+			if n, err := strconv.Atoi(u.Query().Get("page")); err == nil && n > page {
+				return n, true
+			}
+		}
+	}
+	if page < p.TotalPages {
+		return page + 1, true
+	}
+	return 0, false
+}
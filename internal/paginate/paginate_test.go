@@ -0,0 +1,425 @@
+package paginate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+)
+
+// source serves a listing of ints in pages of size, like the API does. The
+// listing can change between requests, as it does when resources are
+// created or deleted while a client pages through it.
+type source struct {
+	mu      sync.Mutex
+	items   []int
+	size    int
+	next    map[int]string            // Next link returned with a page
+	fail    map[int]error             // Error returned for a page
+	before  func(page int, s *source) // Called before a page is served
+	after   func(page int, s *source) // Called after a page is served
+	fetched []int
+}
+
+func newSource(n, size int) *source {
+	s := &source{size: size}
+	for i := 1; i <= n; i++ {
+		s.items = append(s.items, i)
+	}
+	return s
+}
+
+func (s *source) fetch(_ context.Context, page int) (*Page[int], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetched = append(s.fetched, page)
+	if s.before != nil {
+		s.before(page, s)
+	}
+	if err := s.fail[page]; err != nil {
+		return nil, err
+	}
+
+	start := min((page-1)*s.size, len(s.items))
+	end := min(start+s.size, len(s.items))
+	p := &Page[int]{
+		Items:         slices.Clone(s.items[start:end]),
+		Size:          s.size,
+		TotalElements: int64(len(s.items)),
+		TotalPages:    (len(s.items) + s.size - 1) / s.size,
+		Next:          s.next[page],
+	}
+	if s.after != nil {
+		s.after(page, s)
+	}
+	return p, nil
+}
+
+func (s *source) remove(items ...int) {
+	s.items = slices.DeleteFunc(s.items, func(i int) bool { return slices.Contains(items, i) })
+}
+
+func identity(i int) int { return i }
+
+func seq(from, to int) []int {
+	var items []int
+	for i := from; i <= to; i++ {
+		items = append(items, i)
+	}
+	return items
+}
+
+func TestAll(t *testing.T) {
+	errPage := errors.New("page failed")
+
+	tests := []struct {
+		name        string
+		source      func() *source
+		first       int
+		want        []int
+		wantErr     error
+		wantFetched []int
+	}{
+		{
+			name:        "all pages",
+			source:      func() *source { return newSource(10, 3) },
+			want:        seq(1, 10),
+			wantFetched: []int{1, 2, 3, 4},
+		},
+		{
+			name:        "from a later page",
+			source:      func() *source { return newSource(10, 3) },
+			first:       2,
+			want:        seq(4, 10),
+			wantFetched: []int{2, 3, 4},
+		},
+		{
+			name:        "empty listing",
+			source:      func() *source { return newSource(0, 3) },
+			wantFetched: []int{1},
+		},
+		{
+			name: "items inserted before the current page are not yielded twice",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.before = func(page int, s *source) {
+					if page == 2 {
+						s.items = append([]int{100, 101}, s.items...)
+					}
+				}
+				return s
+			},
+			// Page 2 now starts with 2 and 3, which page 1 already returned
+			want:        seq(1, 10),
+			wantFetched: []int{1, 2, 3, 4},
+		},
+		{
+			name: "steps back when items before the current page are deleted",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.before = func(page int, s *source) {
+					if page == 2 && len(s.items) == 10 {
+						s.remove(1, 2)
+					}
+				}
+				return s
+			},
+			// Without stepping back 4 and 5, now on page 1, would be skipped
+			want:        seq(1, 10),
+			wantFetched: []int{1, 2, 1, 2, 3},
+		},
+		{
+			name: "steps back several pages",
+			source: func() *source {
+				s := newSource(12, 2)
+				s.before = func(page int, s *source) {
+					if page == 4 && len(s.items) == 12 {
+						s.remove(1, 2, 3, 4, 5)
+					}
+				}
+				return s
+			},
+			want:        seq(1, 12),
+			wantFetched: []int{1, 2, 3, 4, 1, 2, 3, 4},
+		},
+		{
+			name: "does not step back before the first page",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.before = func(page int, s *source) {
+					if page == 3 && len(s.items) == 10 {
+						s.remove(1, 2, 3, 4, 5, 6)
+					}
+				}
+				return s
+			},
+			// 7, 8 and 9 moved to page 1, which the caller did not ask for
+			first:       2,
+			want:        []int{4, 5, 6, 10},
+			wantFetched: []int{2, 3, 2},
+		},
+		{
+			name: "follows the next link",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.next = map[int]string{1: "/v1/compute/instances?page=3&size=3"}
+				return s
+			},
+			want:        append(seq(1, 3), seq(7, 10)...),
+			wantFetched: []int{1, 3, 4},
+		},
+		{
+			name: "ignores a next link that does not advance",
+			source: func() *source {
+				s := newSource(6, 3)
+				s.next = map[int]string{1: "/v1/compute/instances?page=1", 2: "::"}
+				return s
+			},
+			want:        seq(1, 6),
+			wantFetched: []int{1, 2},
+		},
+		{
+			name: "stops at an empty page",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.next = map[int]string{2: "/v1/compute/instances?page=9"}
+				return s
+			},
+			want:        seq(1, 6),
+			wantFetched: []int{1, 2, 9},
+		},
+		{
+			name: "stops at the first error",
+			source: func() *source {
+				s := newSource(10, 3)
+				s.fail = map[int]error{2: errPage}
+				return s
+			},
+			want:        seq(1, 3),
+			wantErr:     errPage,
+			wantFetched: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.source()
+			got, err := Collect(All(context.Background(), tt.first, s.fetch, identity))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(s.fetched, tt.wantFetched) {
+				t.Errorf("fetched pages %v, want %v", s.fetched, tt.wantFetched)
+			}
+		})
+	}
+}
+
+func TestAllStopsWhenConsumerBreaks(t *testing.T) {
+	s := newSource(10, 3)
+	var got []int
+	for item, err := range All(context.Background(), 1, s.fetch, identity) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, item)
+		if item == 4 {
+			break
+		}
+	}
+	if !slices.Equal(got, seq(1, 4)) || !slices.Equal(s.fetched, []int{1, 2}) {
+		t.Errorf("items = %v, fetched pages %v, want items 1-4 from pages 1 and 2", got, s.fetched)
+	}
+}
+
+func TestAllContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newSource(10, 3)
+	s.before = func(page int, _ *source) {
+		if page == 2 {
+			cancel()
+		}
+	}
+
+	got, err := Collect(All(ctx, 1, s.fetch, identity))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	// Items of a page fetched after cancellation are not yielded
+	if !slices.Equal(got, seq(1, 3)) {
+		t.Errorf("items = %v, want %v", got, seq(1, 3))
+	}
+}
+
+func TestAllDedupeByKey(t *testing.T) {
+	type item struct {
+		id      int
+		version int
+	}
+	fetch := func(_ context.Context, page int) (*Page[item], error) {
+		pages := [][]item{{{1, 1}, {2, 1}}, {{2, 2}, {3, 1}}}
+		return &Page[item]{Items: pages[page-1], Size: 2, TotalElements: 4, TotalPages: 2}, nil
+	}
+
+	got, err := Collect(All(context.Background(), 1, fetch, func(i item) int { return i.id }))
+	want := []item{{1, 1}, {2, 1}, {3, 1}}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("Collect() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	errPage := errors.New("page failed")
+
+	tests := []struct {
+		name         string
+		source       func() *source
+		workers      int
+		want         []int
+		wantErr      error
+		wantPageErrs []int // Pages expected as *apierr.PageError in the joined error
+		wantFetches  int
+	}{
+		{
+			name:        "sequential",
+			source:      func() *source { return newSource(10, 3) },
+			workers:     1,
+			want:        seq(1, 10),
+			wantFetches: 4,
+		},
+		{
+			name:        "concurrent pages reassembled in order",
+			source:      func() *source { return newSource(20, 3) },
+			workers:     3,
+			want:        seq(1, 20),
+			wantFetches: 7,
+		},
+		{
+			name:        "more workers than pages",
+			source:      func() *source { return newSource(5, 2) },
+			workers:     10,
+			want:        seq(1, 5),
+			wantFetches: 3,
+		},
+		{
+			name:        "single page",
+			source:      func() *source { return newSource(3, 3) },
+			workers:     4,
+			want:        seq(1, 3),
+			wantFetches: 1,
+		},
+		{
+			name:        "empty listing",
+			source:      func() *source { return newSource(0, 3) },
+			workers:     4,
+			wantFetches: 1,
+		},
+		{
+			name: "partial results with page errors",
+			source: func() *source {
+				s := newSource(12, 3)
+				s.fail = map[int]error{2: errPage, 4: errPage}
+				return s
+			},
+			workers:      2,
+			want:         append(seq(1, 3), seq(7, 9)...),
+			wantErr:      errPage,
+			wantPageErrs: []int{2, 4},
+			wantFetches:  4,
+		},
+		{
+			name: "first page fails",
+			source: func() *source {
+				s := newSource(12, 3)
+				s.fail = map[int]error{1: errPage}
+				return s
+			},
+			workers:     2,
+			wantErr:     errPage,
+			wantFetches: 1,
+		},
+		{
+			name: "duplicates across pages dropped",
+			source: func() *source {
+				s := newSource(8, 3)
+				s.after = func(page int, s *source) {
+					if page == 1 {
+						// Later pages shift forward by one, still fitting in 3 pages
+						s.items = append([]int{100}, s.items...)
+					}
+				}
+				return s
+			},
+			workers:     2,
+			want:        seq(1, 8),
+			wantFetches: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.source()
+			got, err := List(context.Background(), 1, tt.workers, s.fetch, identity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if len(s.fetched) != tt.wantFetches {
+				t.Errorf("fetched pages %v, want %d fetches", s.fetched, tt.wantFetches)
+			}
+
+			var pageErrs []int
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, err := range joined.Unwrap() {
+					var pageErr *apierr.PageError
+					if !errors.As(err, &pageErr) || !errors.Is(pageErr, errPage) {
+						t.Errorf("error %v is not a *PageError wrapping the page error", err)
+						continue
+					}
+					pageErrs = append(pageErrs, pageErr.Page)
+				}
+			}
+			if !slices.Equal(pageErrs, tt.wantPageErrs) {
+				t.Errorf("failed pages %v, want %v", pageErrs, tt.wantPageErrs)
+			}
+		})
+	}
+}
+
+func TestListContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newSource(30, 3)
+	s.before = func(page int, _ *source) {
+		if page == 2 {
+			cancel()
+		}
+	}
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		p, err := s.fetch(ctx, page)
+		if err == nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return p, err
+	}
+
+	got, err := List(ctx, 1, 2, fetch, identity)
+	if err != context.Canceled {
+		t.Fatalf("error = %v, want context.Canceled reported once", err)
+	}
+	if !slices.Equal(got[:3], seq(1, 3)) {
+		t.Errorf("items = %v, want the first page kept", got)
+	}
+	if len(s.fetched) >= 10 {
+		t.Errorf("fetched all %d pages after cancellation", len(s.fetched))
+	}
+}
@@ -0,0 +1,64 @@
+// Package urlbuild builds the request paths and query strings of API calls.
+// It is shared by the root package and the service packages so that every
+// call escapes and orders its URL the same way.
+package urlbuild
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+)
+
+// Path formats a request path like fmt.Sprintf, escaping every string
+// argument (including named string types) as a single path segment, so that
+// values containing "/", "?", "#", "%" or spaces stay within their segment.
+// Other arguments are formatted unchanged.
+//
+// Empty strings and the dot segments "." and ".." are rejected with an error
+// wrapping apierr.ErrValidation: they would survive escaping and let the
+// request resolve to another resource, e.g. a record ID ".." to its zone.
+func Path(format string, args ...interface{}) (string, error) {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = arg
+		if v := reflect.ValueOf(arg); v.Kind() == reflect.String {
+			segment := v.String()
+			if segment == "" || segment == "." || segment == ".." {
+				return "", fmt.Errorf("invalid path segment %q: %w", segment, apierr.ErrValidation)
+			}
+			escaped[i] = url.PathEscape(segment)
+		}
+	}
+	return fmt.Sprintf(format, escaped...), nil
+}
+
+// Query returns the query string, including the leading "?", for the list
+// parameters and additional params, or "" if there are none. Page and size
+// are omitted if not positive, params if empty. Values are escaped and keys
+// are sorted, so the same parameters always give the same string; repeated
+// orderBy values keep their order.
+func Query(page, size int, orderBy []string, params map[string]string) string {
+	values := url.Values{}
+	if page > 0 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if size > 0 {
+		values.Set("size", strconv.Itoa(size))
+	}
+	for _, order := range orderBy {
+		values.Add("orderBy", order)
+	}
+	for k, v := range params {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
@@ -0,0 +1,110 @@
+package urlbuild
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+)
+
+type region string
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"plain", "/v1/dns/zones/%s/records/%s", []interface{}{"example.com", "42"}, "/v1/dns/zones/example.com/records/42"},
+		{"integer", "/v1/compute/instances/%d/snapshots/%s", []interface{}{int64(100), "snap"}, "/v1/compute/instances/100/snapshots/snap"},
+		{"slash", "/v1/dns/zones/%s", []interface{}{"a.com/../x"}, "/v1/dns/zones/a.com%2F..%2Fx"},
+		{"question mark", "/v1/dns/zones/%s/records/%s", []interface{}{"a.com", "1?x=2"}, "/v1/dns/zones/a.com/records/1%3Fx=2"},
+		{"hash", "/v1/tags/%s", []interface{}{"a#b"}, "/v1/tags/a%23b"},
+		{"space", "/v1/secrets/%s", []interface{}{"my secret"}, "/v1/secrets/my%20secret"},
+		{"ampersand", "/v1/secrets/%s", []interface{}{"a&b"}, "/v1/secrets/a&b"},
+		{"percent", "/v1/secrets/%s", []interface{}{"100%"}, "/v1/secrets/100%25"},
+		{"IPv6", "/v1/dns/ptrs/%s", []interface{}{"2a02:c207::1"}, "/v1/dns/ptrs/2a02:c207::1"},
+		{"IPv4", "/v1/dns/ptrs/%s", []interface{}{"203.0.113.7"}, "/v1/dns/ptrs/203.0.113.7"},
+		{"dots inside segment", "/v1/dns/zones/%s", []interface{}{"..example"}, "/v1/dns/zones/..example"},
+		{"named string type", "/v1/regions/%s", []interface{}{region("US central")}, "/v1/regions/US%20central"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Path(tt.format, tt.args...)
+			if err != nil {
+				t.Fatalf("Path() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+
+			// The path must survive resolution against a base URL unchanged
+			rel, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("url.Parse(%q) error = %v", got, err)
+			}
+			base, _ := url.Parse("https://api.contabo.com")
+			if resolved := base.ResolveReference(rel).EscapedPath(); resolved != got {
+				t.Errorf("resolved path = %q, want %q", resolved, got)
+			}
+		})
+	}
+}
+
+func TestPathRejectsSegments(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		args   []interface{}
+	}{
+		{"empty", "/v1/dns/zones/%s/records", []interface{}{""}},
+		{"dot", "/v1/dns/zones/%s/records", []interface{}{"."}},
+		{"dot dot", "/v1/dns/zones/x/records/%s", []interface{}{".."}},
+		{"second argument", "/v1/dns/zones/%s/records/%s", []interface{}{"x", ".."}},
+		{"named string type", "/v1/regions/%s", []interface{}{region("")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Path(tt.format, tt.args...)
+			if !errors.Is(err, apierr.ErrValidation) {
+				t.Fatalf("Path() = %q, %v, want error wrapping ErrValidation", got, err)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    int
+		size    int
+		orderBy []string
+		params  map[string]string
+		want    string
+	}{
+		{"empty", 0, 0, nil, nil, ""},
+		{"empty params dropped", 0, 0, nil, map[string]string{"name": ""}, ""},
+		{"page and size", 2, 25, nil, nil, "?page=2&size=25"},
+		{"non-positive page and size", -1, 0, nil, map[string]string{"a": "b"}, "?a=b"},
+		{"sorted keys", 1, 10, nil, map[string]string{"region": "EU", "name": "web", "dataCenter": "x"}, "?dataCenter=x&name=web&page=1&region=EU&size=10"},
+		{"repeated orderBy keeps order", 0, 0, []string{"name:asc", "createdDate:desc"}, nil, "?orderBy=name%3Aasc&orderBy=createdDate%3Adesc"},
+		{"escaped values", 0, 0, nil, map[string]string{"name": "web & db", "search": "a=b#c"}, "?name=web+%26+db&search=a%3Db%23c"},
+		{"comma list", 0, 0, nil, map[string]string{"instanceIds": "1,2"}, "?instanceIds=1%2C2"},
+		{"IPv6 value", 0, 0, nil, map[string]string{"ip": "2a02:c207::1"}, "?ip=2a02%3Ac207%3A%3A1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch map iteration order leaking into the result
+			for range 20 {
+				if got := Query(tt.page, tt.size, tt.orderBy, tt.params); got != tt.want {
+					t.Fatalf("Query() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
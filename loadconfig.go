@@ -0,0 +1,200 @@
+package contabo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvClientID     = "CONTABO_CLIENT_ID"
+	EnvClientSecret = "CONTABO_CLIENT_SECRET"
+	EnvUsername     = "CONTABO_API_USER"
+	EnvPassword     = "CONTABO_API_PASSWORD"
+	EnvAuthURL      = "CONTABO_AUTH_URL"
+	EnvBaseURL      = "CONTABO_BASE_URL"
+	EnvProfile      = "CONTABO_PROFILE"
+	EnvConfigFile   = "CONTABO_CONFIG_FILE"
+)
+
+// ErrProfileNotFound is returned when the requested profile does not exist
+var ErrProfileNotFound = errors.New("profile not found")
+
+// LoadOptions controls where LoadConfig looks for settings. Every value is
+// resolved from, in order of precedence: the explicit field, the CONTABO_*
+// environment variable, the selected profile, and the built-in default.
+type LoadOptions struct {
+	// Explicit values
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+	AuthURL      string
+	BaseURL      string
+
+	// Profile to use (defaults to $CONTABO_PROFILE, then the file's
+	// default_profile, then "default")
+	Profile string
+
+	// Profile file to read (defaults to $CONTABO_CONFIG_FILE, then the first
+	// existing file of DefaultConfigFiles)
+	ConfigFile string
+
+	// Environment lookup, defaults to os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+}
+
+// DefaultConfigFiles returns the profile files LoadConfig tries in order:
+// the SDK's config.yaml in the user config directory and cntb's ~/.cntb.yaml
+func DefaultConfigFiles() []string {
+	var files []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		files = append(files, filepath.Join(dir, "contabo", "config.yaml"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".cntb.yaml"))
+	}
+	return files
+}
+
+// LoadConfig builds a Config from explicit values, CONTABO_* environment
+// variables and a named profile. Missing credentials are reported together,
+// naming every source that was consulted.
+func LoadConfig(opts *LoadOptions) (*Config, error) {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	getenv := func(key string) string {
+		v, _ := lookupEnv(key)
+		return strings.TrimSpace(v)
+	}
+
+	profile, profileDesc, err := loadProfile(opts, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := NewConfig("", "", "", "")
+	config := &Config{}
+	fields := []struct {
+		target   *string
+		explicit string
+		env      string
+		profile  string
+		fallback string
+		missing  error
+	}{
+		{&config.ClientID, opts.ClientID, EnvClientID, profile.ClientID, "", ErrMissingClientID},
+		{&config.ClientSecret, opts.ClientSecret, EnvClientSecret, profile.ClientSecret, "", ErrMissingClientSecret},
+		{&config.Username, opts.Username, EnvUsername, profile.Username, "", ErrMissingUsername},
+		{&config.Password, opts.Password, EnvPassword, profile.Password, "", ErrMissingPassword},
+		{&config.AuthURL, opts.AuthURL, EnvAuthURL, profile.AuthURL, defaults.AuthURL, nil},
+		{&config.BaseURL, opts.BaseURL, EnvBaseURL, profile.BaseURL, defaults.BaseURL, nil},
+	}
+
+	var errs []error
+	for _, f := range fields {
+		value := f.explicit
+		if value == "" {
+			value = getenv(f.env)
+		}
+		if value == "" {
+			value = f.profile
+		}
+		if value == "" {
+			value = f.fallback
+		}
+		if value == "" && f.missing != nil {
+			errs = append(errs, fmt.Errorf("%w: not set explicitly, in $%s, or in %s", f.missing, f.env, profileDesc))
+		}
+		*f.target = value
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return config, nil
+}
+
+// loadProfile reads the selected profile and describes where it came from
+func loadProfile(opts *LoadOptions, getenv func(string) string) (Profile, string, error) {
+	path := opts.ConfigFile
+	explicitFile := path != ""
+	if path == "" {
+		path = getenv(EnvConfigFile)
+		explicitFile = path != ""
+	}
+
+	name := opts.Profile
+	if name == "" {
+		name = getenv(EnvProfile)
+	}
+	explicitProfile := name != ""
+
+	var candidates []string
+	if path != "" {
+		candidates = []string{path}
+	} else {
+		candidates = DefaultConfigFiles()
+	}
+
+	for _, candidate := range candidates {
+		file, err := readProfileFile(candidate)
+		if errors.Is(err, fs.ErrNotExist) && !explicitFile {
+			continue
+		}
+		if err != nil {
+			return Profile{}, "", fmt.Errorf("config file %s: %w", candidate, err)
+		}
+
+		if name == "" {
+			name = file.DefaultProfile
+		}
+		if name == "" {
+			name = "default"
+		}
+
+		profile, ok := file.Profiles[name]
+		if !ok && (explicitProfile || len(file.Profiles) > 0) {
+			return Profile{}, "", fmt.Errorf("%w: %q in %s (available: %s)",
+				ErrProfileNotFound, name, candidate, strings.Join(profileNames(file), ", "))
+		}
+		return profile, fmt.Sprintf("profile %q of %s", name, candidate), nil
+	}
+
+	if explicitProfile {
+		return Profile{}, "", fmt.Errorf("%w: %q (no config file found in %s)",
+			ErrProfileNotFound, name, strings.Join(candidates, ", "))
+	}
+	return Profile{}, fmt.Sprintf("a config file (none found in %s)", strings.Join(candidates, ", ")), nil
+}
+
+// readProfileFile opens and parses a profile file
+func readProfileFile(path string) (*ProfileFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseProfileFile(f)
+}
+
+// profileNames returns the sorted profile names of file
+func profileNames(file *ProfileFile) []string {
+	names := make([]string, 0, len(file.Profiles))
+	for name := range file.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
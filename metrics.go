@@ -0,0 +1,63 @@
+package contabo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APICallMetric describes a completed API call, including all retries
+type APICallMetric struct {
+	Service      string
+	Operation    string
+	Method       string
+	PathTemplate string
+	StatusCode   int    // 0 if no response was received
+	StatusClass  string // "2xx", "4xx", "5xx", ... or "error" without a response
+	Duration     time.Duration
+}
+
+// TokenRefreshMetric describes a token request made by AuthManager
+type TokenRefreshMetric struct {
+	GrantType string // "password" or "refresh_token"
+	Success   bool
+	Duration  time.Duration
+}
+
+// MetricsCollector receives metrics about API calls and token refreshes.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	ObserveAPICall(m APICallMetric)
+	ObserveTokenRefresh(m TokenRefreshMetric)
+}
+
+// noopMetrics is the default MetricsCollector
+type noopMetrics struct{}
+
+// ObserveAPICall implements MetricsCollector
+func (noopMetrics) ObserveAPICall(APICallMetric) {}
+
+// ObserveTokenRefresh implements MetricsCollector
+func (noopMetrics) ObserveTokenRefresh(TokenRefreshMetric) {}
+
+// statusClass returns the status class label for a status code
+func statusClass(statusCode int) string {
+	if statusCode < 100 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+// callStatusCode returns the HTTP status of a call's outcome, or 0 if no
+// response was received
+func callStatusCode(resp *http.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,208 @@
+package contabo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the histogram buckets, in seconds, used by
+// PrometheusMetrics when none are given
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is a MetricsCollector that keeps counters and histograms
+// in memory and serves them in the Prometheus text exposition format
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu            sync.Mutex
+	calls         map[apiCallKey]*histogram
+	refreshes     map[string]float64
+	refreshErrors map[string]float64
+}
+
+// apiCallKey holds the labels of the API call metrics
+type apiCallKey struct {
+	service, operation, method, path, statusClass string
+}
+
+// histogram holds the samples of one histogram series
+type histogram struct {
+	counts []uint64 // One per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates a collector using the given histogram buckets
+// in seconds, or DefaultDurationBuckets if none are given
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:       buckets,
+		calls:         make(map[apiCallKey]*histogram),
+		refreshes:     make(map[string]float64),
+		refreshErrors: make(map[string]float64),
+	}
+}
+
+// ObserveAPICall implements MetricsCollector
+func (p *PrometheusMetrics) ObserveAPICall(m APICallMetric) {
+	key := apiCallKey{m.Service, m.Operation, m.Method, m.PathTemplate, m.StatusClass}
+	seconds := m.Duration.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h := p.calls[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.calls[key] = h
+	}
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObserveTokenRefresh implements MetricsCollector
+func (p *PrometheusMetrics) ObserveTokenRefresh(m TokenRefreshMetric) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.refreshes[m.GrantType]++
+	if !m.Success {
+		p.refreshErrors[m.GrantType]++
+	}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format,
+// e.g. to append them to the output of an existing /metrics handler
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	keys := make([]apiCallKey, 0, len(p.calls))
+	for key := range p.calls {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.service != b.service {
+			return a.service < b.service
+		}
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.statusClass < b.statusClass
+	})
+
+	fmt.Fprintln(cw, "# HELP contabo_api_requests_total Contabo API calls by operation and status class.")
+	fmt.Fprintln(cw, "# TYPE contabo_api_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(cw, "contabo_api_requests_total{%s} %d\n", key.labels(), p.calls[key].count)
+	}
+
+	fmt.Fprintln(cw, "# HELP contabo_api_request_duration_seconds Duration of Contabo API calls including retries.")
+	fmt.Fprintln(cw, "# TYPE contabo_api_request_duration_seconds histogram")
+	for _, key := range keys {
+		h := p.calls[key]
+		labels := key.labels()
+
+		var cumulative uint64
+		for i, bound := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(cw, "contabo_api_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(cw, "contabo_api_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(cw, "contabo_api_request_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(cw, "contabo_api_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	writeCounterVec(cw, "contabo_token_refreshes_total", "Token requests made by the SDK.", p.refreshes)
+	writeCounterVec(cw, "contabo_token_refresh_failures_total", "Failed token requests made by the SDK.", p.refreshErrors)
+
+	if err := bw.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+// labels formats the label set of an API call metric
+func (k apiCallKey) labels() string {
+	return fmt.Sprintf(`service="%s",operation="%s",method="%s",path="%s",status_class="%s"`,
+		escapeLabel(k.service), escapeLabel(k.operation), escapeLabel(k.method),
+		escapeLabel(k.path), escapeLabel(k.statusClass))
+}
+
+// writeCounterVec writes a counter labelled by grant type
+func writeCounterVec(w io.Writer, name, help string, values map[string]float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+
+	grants := make([]string, 0, len(values))
+	for grant := range values {
+		grants = append(grants, grant)
+	}
+	sort.Strings(grants)
+
+	for _, grant := range grants {
+		fmt.Fprintf(w, "%s{grant_type=\"%s\"} %s\n", name, escapeLabel(grant), formatFloat(values[grant]))
+	}
+}
+
+// escapeLabel escapes a label value for the text exposition format
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// formatFloat formats a sample value or bucket bound
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countingWriter counts bytes written and remembers the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
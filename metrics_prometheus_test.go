@@ -0,0 +1,78 @@
+package contabo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetricsWriteTo(t *testing.T) {
+	m := NewPrometheusMetrics(1, 0.1) // Buckets are sorted
+	m.ObserveAPICall(APICallMetric{Service: "compute", Operation: "GetInstance", Method: "GET", PathTemplate: "/v1/compute/instances/{instanceId}", StatusClass: "2xx", Duration: 50 * time.Millisecond})
+	m.ObserveAPICall(APICallMetric{Service: "compute", Operation: "GetInstance", Method: "GET", PathTemplate: "/v1/compute/instances/{instanceId}", StatusClass: "2xx", Duration: 500 * time.Millisecond})
+	m.ObserveAPICall(APICallMetric{Service: "compute", Operation: "GetInstance", Method: "GET", PathTemplate: "/v1/compute/instances/{instanceId}", StatusClass: "2xx", Duration: 2 * time.Second})
+	m.ObserveAPICall(APICallMetric{Method: "GET", PathTemplate: `a"b\c` + "\nd", StatusClass: "error", Duration: 100 * time.Millisecond})
+	m.ObserveTokenRefresh(TokenRefreshMetric{GrantType: "password", Success: true})
+	m.ObserveTokenRefresh(TokenRefreshMetric{GrantType: "refresh_token", Success: true})
+	m.ObserveTokenRefresh(TokenRefreshMetric{GrantType: "refresh_token", Success: false})
+
+	want := `# HELP contabo_api_requests_total Contabo API calls by operation and status class.
+# TYPE contabo_api_requests_total counter
+contabo_api_requests_total{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error"} 1
+contabo_api_requests_total{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx"} 3
+# HELP contabo_api_request_duration_seconds Duration of Contabo API calls including retries.
+# TYPE contabo_api_request_duration_seconds histogram
+contabo_api_request_duration_seconds_bucket{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error",le="0.1"} 1
+contabo_api_request_duration_seconds_bucket{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error",le="1"} 1
+contabo_api_request_duration_seconds_bucket{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error",le="+Inf"} 1
+contabo_api_request_duration_seconds_sum{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error"} 0.1
+contabo_api_request_duration_seconds_count{service="",operation="",method="GET",path="a\"b\\c\nd",status_class="error"} 1
+contabo_api_request_duration_seconds_bucket{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx",le="0.1"} 1
+contabo_api_request_duration_seconds_bucket{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx",le="1"} 2
+contabo_api_request_duration_seconds_bucket{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx",le="+Inf"} 3
+contabo_api_request_duration_seconds_sum{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx"} 2.55
+contabo_api_request_duration_seconds_count{service="compute",operation="GetInstance",method="GET",path="/v1/compute/instances/{instanceId}",status_class="2xx"} 3
+# HELP contabo_token_refreshes_total Token requests made by the SDK.
+# TYPE contabo_token_refreshes_total counter
+contabo_token_refreshes_total{grant_type="password"} 1
+contabo_token_refreshes_total{grant_type="refresh_token"} 2
+# HELP contabo_token_refresh_failures_total Failed token requests made by the SDK.
+# TYPE contabo_token_refresh_failures_total counter
+contabo_token_refresh_failures_total{grant_type="refresh_token"} 1
+`
+
+	var b strings.Builder
+	n, err := m.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", got, want)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, b.Len())
+	}
+}
+
+func TestPrometheusMetricsEmpty(t *testing.T) {
+	want := `# HELP contabo_api_requests_total Contabo API calls by operation and status class.
+# TYPE contabo_api_requests_total counter
+# HELP contabo_api_request_duration_seconds Duration of Contabo API calls including retries.
+# TYPE contabo_api_request_duration_seconds histogram
+# HELP contabo_token_refreshes_total Token requests made by the SDK.
+# TYPE contabo_token_refreshes_total counter
+# HELP contabo_token_refresh_failures_total Failed token requests made by the SDK.
+# TYPE contabo_token_refresh_failures_total counter
+`
+
+	rec := httptest.NewRecorder()
+	NewPrometheusMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body =\n%s\nwant\n%s", got, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the text exposition format", ct)
+	}
+}
@@ -0,0 +1,101 @@
+package contabo
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Call describes a single API call as it passes through the middleware chain
+type Call struct {
+	Route              // Method, path template, service and operation
+	Path   string      // Request path including query string
+	Body   interface{} // Request body, marshalled to JSON (nil for none)
+	Result interface{} // Destination the response is decoded into (may be nil)
+	Header http.Header // Extra headers added to the HTTP request
+}
+
+// Handler performs an API call. The response is decoded into call.Result;
+// API failures are returned as *APIError.
+type Handler func(ctx context.Context, call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler to add behaviour around API calls. A middleware
+// may modify the call, short-circuit by not calling next, or inspect the
+// decoded result and error returned by next.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client's chain. Middleware runs in the order
+// added, the first one being the outermost. Use is not safe to call
+// concurrently with requests.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+	c.handler = c.buildHandler()
+}
+
+// buildHandler composes the middleware chain around the transport handler.
+// In dry-run mode, mutating calls stop just before the transport, so all
+// middleware still sees them.
+func (c *Client) buildHandler() Handler {
+	h := Handler(c.send)
+	if c.config.DryRun != nil {
+		h = c.dryRun(c.config.DryRun)(h)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// call runs an API call through the middleware chain
+func (c *Client) call(ctx context.Context, method, path string, body, v interface{}) (*http.Response, error) {
+	call := &Call{
+		Route:  MatchRoute(method, path),
+		Path:   path,
+		Body:   body,
+		Result: v,
+		Header: make(http.Header),
+	}
+
+	// Fix the request ID up front so that tracing, middleware and every
+	// attempt of the call share it
+	if _, ok := RequestIDFromContext(ctx); !ok {
+		ctx = WithRequestID(ctx, uuid.New().String())
+	}
+
+	start := time.Now()
+	resp, err := c.trace(ctx, call, c.handler)
+	duration := time.Since(start)
+
+	statusCode := callStatusCode(resp, err)
+	c.metrics.ObserveAPICall(APICallMetric{
+		Service:      call.Service,
+		Operation:    call.Operation,
+		Method:       call.Method,
+		PathTemplate: call.PathTemplate,
+		StatusCode:   statusCode,
+		StatusClass:  statusClass(statusCode),
+		Duration:     duration,
+	})
+	observeResponse(ctx, resp, err, duration)
+
+	return resp, err
+}
+
+// send is the innermost handler: it builds the HTTP request and executes it
+func (c *Client) send(ctx context.Context, call *Call) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, call.Method, call.Path, call.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range call.Header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	return c.Do(req, call.Result)
+}
@@ -0,0 +1,48 @@
+package network
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllPrivateNetworks returns an iterator over the private networks of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllPrivateNetworks(ctx context.Context, opts *ListPrivateNetworksOptions) iter.Seq2[PrivateNetwork, error] {
+	return paginate.All(ctx, privateNetworkFields.StartPage(opts), s.privateNetworkPages(opts), privateNetworkKey)
+}
+
+// ListAllPrivateNetworks retrieves the private networks of all pages, fetching up to
+// opts.Concurrency pages at once. On error the private networks read so far are
+// returned with it.
+func (s *Service) ListAllPrivateNetworks(ctx context.Context, opts *ListPrivateNetworksOptions) ([]PrivateNetwork, error) {
+	return paginate.List(ctx, privateNetworkFields.StartPage(opts), privateNetworkFields.Concurrency(opts), s.privateNetworkPages(opts), privateNetworkKey)
+}
+
+// privateNetworkPages returns a function fetching a page of private networks
+func (s *Service) privateNetworkPages(opts *ListPrivateNetworksOptions) paginate.Fetch[PrivateNetwork] {
+	return func(ctx context.Context, page int) (*paginate.Page[PrivateNetwork], error) {
+		resp, err := s.ListPrivateNetworks(ctx, privateNetworkFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[PrivateNetwork]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// privateNetworkKey identifies a private network for de-duplication
+func privateNetworkKey(p PrivateNetwork) int64 {
+	return p.PrivateNetworkID
+}
+
+// privateNetworkFields reaches the pagination fields of ListPrivateNetworksOptions for internal/paginate
+var privateNetworkFields paginate.Fields[ListPrivateNetworksOptions] = func(o *ListPrivateNetworksOptions) (page, concurrency *int) {
+	return &o.Page, &o.Concurrency
+}
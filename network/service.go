@@ -3,6 +3,11 @@ package network
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +24,35 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListPrivateNetworksOptions filters the private networks returned by ListPrivateNetworks.
+// Empty fields are not sent.
+type ListPrivateNetworksOptions struct {
+	ListOptions
+
+	Name        string
+	Region      string
+	DataCenter  string
+	InstanceIDs []int64 // Networks any of these instances are assigned to
+}
+
+// params returns the filter query parameters
+func (o *ListPrivateNetworksOptions) params() map[string]string {
+	ids := make([]string, len(o.InstanceIDs))
+	for i, id := range o.InstanceIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return map[string]string{
+		"name":        o.Name,
+		"region":      o.Region,
+		"dataCenter":  o.DataCenter,
+		"instanceIds": strings.Join(ids, ","),
+	}
 }
 
 // Service handles network-related API operations
@@ -32,10 +66,10 @@ func NewService(client Client) *Service {
 }
 
 // ListPrivateNetworks retrieves a list of private networks
-func (s *Service) ListPrivateNetworks(ctx context.Context, opts *ListOptions) (*PrivateNetworksResponse, error) {
+func (s *Service) ListPrivateNetworks(ctx context.Context, opts *ListPrivateNetworksOptions) (*PrivateNetworksResponse, error) {
 	path := "/v1/private-networks"
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp PrivateNetworksResponse
@@ -48,7 +82,10 @@ func (s *Service) ListPrivateNetworks(ctx context.Context, opts *ListOptions) (*
 
 // GetPrivateNetwork retrieves a specific private network by ID
 func (s *Service) GetPrivateNetwork(ctx context.Context, privateNetworkID int64) (*PrivateNetwork, error) {
-	path := fmt.Sprintf("/v1/private-networks/%d", privateNetworkID)
+	path, err := urlbuild.Path("/v1/private-networks/%d", privateNetworkID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []PrivateNetwork `json:"data"`
@@ -58,7 +95,7 @@ func (s *Service) GetPrivateNetwork(ctx context.Context, privateNetworkID int64)
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("private network not found")
+		return nil, fmt.Errorf("private network %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -82,7 +119,10 @@ func (s *Service) CreatePrivateNetwork(ctx context.Context, req *CreatePrivateNe
 
 // UpdatePrivateNetwork updates a private network
 func (s *Service) UpdatePrivateNetwork(ctx context.Context, privateNetworkID int64, req *PatchPrivateNetworkRequest) (*PrivateNetwork, error) {
-	path := fmt.Sprintf("/v1/private-networks/%d", privateNetworkID)
+	path, err := urlbuild.Path("/v1/private-networks/%d", privateNetworkID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []PrivateNetwork `json:"data"`
@@ -100,59 +140,35 @@ func (s *Service) UpdatePrivateNetwork(ctx context.Context, privateNetworkID int
 
 // DeletePrivateNetwork deletes a private network
 func (s *Service) DeletePrivateNetwork(ctx context.Context, privateNetworkID int64) error {
-	path := fmt.Sprintf("/v1/private-networks/%d", privateNetworkID)
+	path, err := urlbuild.Path("/v1/private-networks/%d", privateNetworkID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // AssignInstances assigns instances to a private network
 func (s *Service) AssignInstances(ctx context.Context, privateNetworkID int64, req *AssignInstanceRequest) error {
-	path := fmt.Sprintf("/v1/private-networks/%d/instances", privateNetworkID)
+	path, err := urlbuild.Path("/v1/private-networks/%d/instances", privateNetworkID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, req, nil)
 }
 
 // UnassignInstances unassigns instances from a private network
 func (s *Service) UnassignInstances(ctx context.Context, privateNetworkID int64, req *UnassignInstanceRequest) error {
-	path := fmt.Sprintf("/v1/private-networks/%d/instances", privateNetworkID)
+	path, err := urlbuild.Path("/v1/private-networks/%d/instances", privateNetworkID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
-	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
-	}
-
-	if len(values) == 0 {
-		return ""
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
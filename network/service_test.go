@@ -0,0 +1,67 @@
+package network_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/network"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListPrivateNetworksQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *network.ListPrivateNetworksOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"empty options", &network.ListPrivateNetworksOptions{}, ""},
+		{"name", &network.ListPrivateNetworksOptions{Name: "backend"}, "name=backend"},
+		{"region and data center", &network.ListPrivateNetworksOptions{Region: "EU", DataCenter: "European Union 1"}, "dataCenter=European+Union+1&region=EU"},
+		{"instance IDs joined", &network.ListPrivateNetworksOptions{InstanceIDs: []int64{100, 200}}, "instanceIds=100%2C200"},
+		{"empty instance IDs omitted", &network.ListPrivateNetworksOptions{InstanceIDs: []int64{}}, ""},
+		{
+			"all filters",
+			&network.ListPrivateNetworksOptions{ListOptions: network.ListOptions{Page: 1, Size: 20}, Name: "backend", Region: "US-east", DataCenter: "United States East 1", InstanceIDs: []int64{7}},
+			"dataCenter=United+States+East+1&instanceIds=7&name=backend&page=1&region=US-east&size=20",
+		},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.Network.ListPrivateNetworks(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListPrivateNetworks() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
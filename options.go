@@ -0,0 +1,178 @@
+package contabo
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout is the HTTP timeout used when no HTTP client or timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is the User-Agent sent when none is configured
+const DefaultUserAgent = "contabo-api-golang"
+
+// Option configures a Client created by NewClient or NewSDK. Options take
+// precedence over the corresponding Config fields and never modify the Config.
+type Option func(*options) error
+
+// options collects the values set by Options
+type options struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    *time.Duration
+	userAgent  string
+	baseURL    string
+	authURL    string
+	logger     *slog.Logger
+	dryRun     *Plan
+}
+
+// WithHTTPClient sets the HTTP client used for API and authentication requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		if httpClient == nil {
+			return errors.New("WithHTTPClient: client must not be nil")
+		}
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the transport of the HTTP client. A client given with
+// WithHTTPClient or Config.HTTPClient is copied, not modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) error {
+		if transport == nil {
+			return errors.New("WithTransport: transport must not be nil")
+		}
+		o.transport = transport
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request (0 means no timeout). A
+// client given with WithHTTPClient or Config.HTTPClient is copied, not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		if timeout < 0 {
+			return fmt.Errorf("WithTimeout: timeout must not be negative, got %s", timeout)
+		}
+		o.timeout = &timeout
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of API and authentication requests
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		if userAgent == "" {
+			return errors.New("WithUserAgent: user agent must not be empty")
+		}
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithBaseURL sets the API base URL
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		if err := validateURL(baseURL); err != nil {
+			return fmt.Errorf("WithBaseURL: %w", err)
+		}
+		o.baseURL = baseURL
+		return nil
+	}
+}
+
+// WithAuthURL sets the OAuth2 token endpoint
+func WithAuthURL(authURL string) Option {
+	return func(o *options) error {
+		if err := validateURL(authURL); err != nil {
+			return fmt.Errorf("WithAuthURL: %w", err)
+		}
+		o.authURL = authURL
+		return nil
+	}
+}
+
+// WithLogger sets the structured logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) error {
+		if logger == nil {
+			return errors.New("WithLogger: logger must not be nil")
+		}
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithDryRun records mutating calls in plan instead of sending them
+func WithDryRun(plan *Plan) Option {
+	return func(o *options) error {
+		if plan == nil {
+			return errors.New("WithDryRun: plan must not be nil")
+		}
+		o.dryRun = plan
+		return nil
+	}
+}
+
+// applyOptions returns a copy of config with the options applied
+func applyOptions(config *Config, opts []Option) (*Config, error) {
+	if config == nil {
+		return nil, errors.New("config must not be nil")
+	}
+
+	var o options
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&o); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	cfg := *config
+	if o.baseURL != "" {
+		cfg.BaseURL = o.baseURL
+	}
+	if o.authURL != "" {
+		cfg.AuthURL = o.authURL
+	}
+	if o.userAgent != "" {
+		cfg.UserAgent = o.userAgent
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultUserAgent
+	}
+	if o.logger != nil {
+		cfg.Logger = o.logger
+	}
+	if o.dryRun != nil {
+		cfg.DryRun = o.dryRun
+	}
+
+	httpClient := cfg.HTTPClient
+	if o.httpClient != nil {
+		httpClient = o.httpClient
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	} else if o.transport != nil || o.timeout != nil {
+		copied := *httpClient
+		httpClient = &copied
+	}
+	if o.transport != nil {
+		httpClient.Transport = o.transport
+	}
+	if o.timeout != nil {
+		httpClient.Timeout = *o.timeout
+	}
+	cfg.HTTPClient = httpClient
+
+	return &cfg, nil
+}
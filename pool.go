@@ -0,0 +1,247 @@
+package contabo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mithucste30/contabo-api-golang/compute"
+)
+
+// ErrAccountExists is returned when adding an account name that is already in the pool
+var ErrAccountExists = errors.New("account already exists")
+
+// ErrAccountNotFound is returned when an account name is not in the pool
+var ErrAccountNotFound = errors.New("account not found")
+
+// PoolConfig holds the settings shared by all accounts of an AccountPool
+type PoolConfig struct {
+	// Optional HTTP client shared by all accounts, so they reuse one transport
+	// and its connections (defaults to one with DefaultTimeout). Accounts with
+	// their own Config.HTTPClient keep it.
+	HTTPClient *http.Client
+
+	// Optional rate limit budget shared by all accounts. Accounts with their own
+	// Config.RateLimit or Config.RateLimiter are limited separately.
+	RateLimit *RateLimit
+
+	// Options applied to every account before the account's own options
+	Options []Option
+}
+
+// AccountPool holds one SDK per named Contabo customer account. Every account
+// authenticates with its own AuthManager; the HTTP transport and, if
+// configured, the rate limit budget are shared.
+type AccountPool struct {
+	httpClient  *http.Client
+	rateLimiter *RateLimiter
+	options     []Option
+
+	mu       sync.RWMutex
+	accounts map[string]*SDK
+}
+
+// NewAccountPool creates an empty account pool
+func NewAccountPool(config *PoolConfig) *AccountPool {
+	if config == nil {
+		config = &PoolConfig{}
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &AccountPool{
+		httpClient:  httpClient,
+		rateLimiter: NewRateLimiter(config.RateLimit),
+		options:     config.Options,
+		accounts:    make(map[string]*SDK),
+	}
+}
+
+// Add creates an SDK for config and adds it to the pool under name
+func (p *AccountPool) Add(name string, config *Config, opts ...Option) (*SDK, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: account name must not be empty", ErrInvalidConfig)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("account %s: %w: config must not be nil", name, ErrInvalidConfig)
+	}
+
+	cfg := *config
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = p.httpClient
+	}
+	if cfg.RateLimiter == nil && cfg.RateLimit == nil {
+		cfg.RateLimiter = p.rateLimiter
+	}
+
+	allOpts := make([]Option, 0, len(p.options)+len(opts))
+	allOpts = append(allOpts, p.options...)
+	allOpts = append(allOpts, opts...)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.accounts[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, name)
+	}
+
+	sdk, err := NewSDK(&cfg, allOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("account %s: %w", name, err)
+	}
+	p.accounts[name] = sdk
+
+	return sdk, nil
+}
+
+// Get returns the SDK of the named account
+func (p *AccountPool) Get(name string) (*SDK, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	sdk, ok := p.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+	}
+	return sdk, nil
+}
+
+// Remove removes the named account from the pool
+func (p *AccountPool) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.accounts, name)
+}
+
+// Names returns the sorted account names of the pool
+func (p *AccountPool) Names() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, 0, len(p.accounts))
+	for name := range p.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RateLimiter returns the rate limiter shared by the pool's accounts, or nil if none is configured
+func (p *AccountPool) RateLimiter() *RateLimiter {
+	return p.rateLimiter
+}
+
+// AccountResult is the result of a fan-out call for one account
+type AccountResult[T any] struct {
+	Account string
+	Value   T
+}
+
+// AccountErrors collects the errors of a fan-out call by account name
+type AccountErrors map[string]error
+
+// Error implements the error interface
+func (e AccountErrors) Error() string {
+	names := e.accounts()
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fmt.Sprintf("account %s: %v", name, e[name])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the account errors so errors.Is and errors.As see each of them
+func (e AccountErrors) Unwrap() []error {
+	names := e.accounts()
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = e[name]
+	}
+	return errs
+}
+
+// accounts returns the sorted account names with an error
+func (e AccountErrors) accounts() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForEachAccount calls fn concurrently for every account of the pool and
+// returns the results of the successful accounts in account name order.
+// Failed accounts are reported in an AccountErrors; the results of the other
+// accounts are returned alongside it.
+func ForEachAccount[T any](ctx context.Context, p *AccountPool, fn func(ctx context.Context, account string, sdk *SDK) (T, error)) ([]AccountResult[T], error) {
+	p.mu.RLock()
+	names := make([]string, 0, len(p.accounts))
+	for name := range p.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sdks := make([]*SDK, len(names))
+	for i, name := range names {
+		sdks[i] = p.accounts[name]
+	}
+	p.mu.RUnlock()
+
+	values := make([]T, len(names))
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = fn(ctx, names[i], sdks[i])
+		}()
+	}
+	wg.Wait()
+
+	var results []AccountResult[T]
+	accountErrs := make(AccountErrors)
+	for i, name := range names {
+		if errs[i] != nil {
+			accountErrs[name] = errs[i]
+			continue
+		}
+		results = append(results, AccountResult[T]{Account: name, Value: values[i]})
+	}
+
+	if len(accountErrs) > 0 {
+		return results, accountErrs
+	}
+	return results, nil
+}
+
+// AccountInstance is a compute instance tagged with its account
+type AccountInstance struct {
+	Account string
+	compute.Instance
+}
+
+// ListInstances lists all instances of every account with the same options,
+// following every page of each account
+func (p *AccountPool) ListInstances(ctx context.Context, opts *compute.ListInstancesOptions) ([]AccountInstance, error) {
+	results, err := ForEachAccount(ctx, p, func(ctx context.Context, _ string, sdk *SDK) ([]compute.Instance, error) {
+		return sdk.Compute.ListAllInstances(ctx, opts)
+	})
+
+	var instances []AccountInstance
+	for _, r := range results {
+		for _, instance := range r.Value {
+			instances = append(instances, AccountInstance{Account: r.Account, Instance: instance})
+		}
+	}
+	return instances, err
+}
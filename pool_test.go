@@ -0,0 +1,53 @@
+package contabo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+)
+
+func TestAccountPoolListInstances(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *compute.ListInstancesOptions
+		want map[string]int
+	}{
+		{"default options", nil, map[string]int{"prod": 150, "staging": 3}},
+		{"small pages", &compute.ListInstancesOptions{ListOptions: compute.ListOptions{Size: 7}}, map[string]int{"prod": 150, "staging": 3}},
+		{"filtered", &compute.ListInstancesOptions{DisplayName: "prod-7"}, map[string]int{"prod": 1, "staging": 0}},
+	}
+
+	pool := contabo.NewAccountPool(nil)
+	for account, n := range map[string]int{"prod": 150, "staging": 3} {
+		server := contabotest.NewServer()
+		t.Cleanup(server.Close)
+		for i := range n {
+			server.AddInstance(compute.Instance{DisplayName: fmt.Sprintf("%s-%d", account, i)})
+		}
+		if _, err := pool.Add(account, server.Config()); err != nil {
+			t.Fatalf("Add(%q) error = %v", account, err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances, err := pool.ListInstances(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("ListInstances() error = %v", err)
+			}
+			got := make(map[string]int)
+			for _, instance := range instances {
+				got[instance.Account]++
+			}
+			for account, want := range tt.want {
+				if got[account] != want {
+					t.Errorf("account %s: got %d instances, want %d", account, got[account], want)
+				}
+			}
+		})
+	}
+}
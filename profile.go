@@ -0,0 +1,243 @@
+package contabo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Profile holds the credentials and endpoints of one named profile
+type Profile struct {
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+	AuthURL      string
+	BaseURL      string
+}
+
+// ProfileFile is a parsed configuration file with named profiles.
+//
+// Two layouts are understood. The SDK's own format lists profiles by name:
+//
+//	default_profile: prod
+//	profiles:
+//	  prod:
+//	    client_id: ...
+//	    client_secret: ...
+//	    username: api-user@example.com
+//	    password: ...
+//	    auth_url: https://auth.contabo.com/auth/realms/contabo/protocol/openid-connect/token
+//	    base_url: https://api.contabo.com
+//
+// The flat format written by Contabo's cntb CLI (oauth2-clientid,
+// oauth2-client-secret, oauth2-user, oauth2-password, oauth2-tokenurl, api)
+// is read as a single profile named "default".
+type ProfileFile struct {
+	DefaultProfile string
+	Profiles       map[string]Profile
+}
+
+// profileKeys maps the keys accepted in a profile to setters
+var profileKeys = map[string]func(p *Profile, v string){
+	"client_id":            func(p *Profile, v string) { p.ClientID = v },
+	"oauth2-clientid":      func(p *Profile, v string) { p.ClientID = v },
+	"client_secret":        func(p *Profile, v string) { p.ClientSecret = v },
+	"oauth2-client-secret": func(p *Profile, v string) { p.ClientSecret = v },
+	"username":             func(p *Profile, v string) { p.Username = v },
+	"api_user":             func(p *Profile, v string) { p.Username = v },
+	"oauth2-user":          func(p *Profile, v string) { p.Username = v },
+	"password":             func(p *Profile, v string) { p.Password = v },
+	"api_password":         func(p *Profile, v string) { p.Password = v },
+	"oauth2-password":      func(p *Profile, v string) { p.Password = v },
+	"auth_url":             func(p *Profile, v string) { p.AuthURL = v },
+	"oauth2-tokenurl":      func(p *Profile, v string) { p.AuthURL = v },
+	"base_url":             func(p *Profile, v string) { p.BaseURL = v },
+	"api":                  func(p *Profile, v string) { p.BaseURL = v },
+}
+
+// ParseProfileFile parses a configuration file in either supported layout
+func ParseProfileFile(r io.Reader) (*ProfileFile, error) {
+	doc, err := parseYAMLMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &ProfileFile{Profiles: make(map[string]Profile)}
+
+	if profiles, ok := doc["profiles"]; ok {
+		profileMap, ok := profiles.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profiles must be a mapping of profile names")
+		}
+		for name, raw := range profileMap {
+			fields, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("profile %q must be a mapping", name)
+			}
+			profile, err := parseProfile(fields)
+			if err != nil {
+				return nil, fmt.Errorf("profile %q: %w", name, err)
+			}
+			file.Profiles[name] = profile
+		}
+		if name, ok := doc["default_profile"].(string); ok {
+			file.DefaultProfile = name
+		}
+		return file, nil
+	}
+
+	// Flat layout, e.g. ~/.cntb.yaml
+	profile, err := parseProfile(doc)
+	if err != nil {
+		return nil, err
+	}
+	file.Profiles["default"] = profile
+	file.DefaultProfile = "default"
+	return file, nil
+}
+
+// parseProfile reads the known keys of a profile, ignoring unknown scalars
+func parseProfile(fields map[string]interface{}) (Profile, error) {
+	var p Profile
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		set, known := profileKeys[strings.ToLower(key)]
+		if !known {
+			continue
+		}
+		value, ok := fields[key].(string)
+		if !ok {
+			return p, fmt.Errorf("%s must be a string", key)
+		}
+		set(&p, value)
+	}
+	return p, nil
+}
+
+// yamlFrame is an open mapping and the indentation of its keys
+type yamlFrame struct {
+	indent int
+	m      map[string]interface{}
+}
+
+// parseYAMLMap parses the subset of YAML used by configuration files:
+// nested mappings of scalar values, comments and quoted strings
+func parseYAMLMap(r io.Reader) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	stack := []yamlFrame{{indent: 0, m: root}}
+
+	// pending is a key whose value is a nested mapping starting on the next line
+	var pendingKey string
+	var pendingParent map[string]interface{}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+		indent := len(line) - len(content)
+
+		if pendingParent != nil {
+			child := make(map[string]interface{})
+			if indent <= stack[len(stack)-1].indent {
+				// The key had no nested values; treat it as empty
+				pendingParent[pendingKey] = ""
+			} else {
+				pendingParent[pendingKey] = child
+				stack = append(stack, yamlFrame{indent: indent, m: child})
+			}
+			pendingParent = nil
+		}
+
+		for len(stack) > 1 && indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		if indent != stack[len(stack)-1].indent {
+			return nil, fmt.Errorf("line %d: inconsistent indentation", lineNo)
+		}
+		current := stack[len(stack)-1].m
+
+		key, rest, ok := strings.Cut(content, ":")
+		if !ok || strings.HasPrefix(content, "- ") {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key = unquoteYAML(strings.TrimSpace(key))
+
+		value, err := parseYAMLScalar(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if value == "" && strings.TrimSpace(rest) == "" {
+			pendingKey, pendingParent = key, current
+			continue
+		}
+		current[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if pendingParent != nil {
+		pendingParent[pendingKey] = ""
+	}
+
+	return root, nil
+}
+
+// parseYAMLScalar parses a plain, single-quoted or double-quoted scalar and
+// strips trailing comments
+func parseYAMLScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				return strconv.Unquote(s[:i+1])
+			}
+		}
+		return "", fmt.Errorf("unterminated double-quoted string")
+	case strings.HasPrefix(s, "'"):
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				return b.String(), nil
+			}
+			b.WriteByte(s[i])
+		}
+		return "", fmt.Errorf("unterminated single-quoted string")
+	}
+
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
+
+// unquoteYAML removes quotes around a mapping key
+func unquoteYAML(s string) string {
+	if v, err := parseYAMLScalar(s); err == nil {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,178 @@
+package contabo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "flat",
+			input: "a: 1\nb: two\n",
+			want:  map[string]interface{}{"a": "1", "b": "two"},
+		},
+		{
+			name:  "nested",
+			input: "profiles:\n  prod:\n    client_id: x\n  staging:\n    client_id: y\ndefault_profile: prod\n",
+			want: map[string]interface{}{
+				"profiles": map[string]interface{}{
+					"prod":    map[string]interface{}{"client_id": "x"},
+					"staging": map[string]interface{}{"client_id": "y"},
+				},
+				"default_profile": "prod",
+			},
+		},
+		{
+			name:  "four space indentation",
+			input: "profiles:\n    prod:\n        client_id: x\n",
+			want:  map[string]interface{}{"profiles": map[string]interface{}{"prod": map[string]interface{}{"client_id": "x"}}},
+		},
+		{
+			name:  "dedent by several levels",
+			input: "a:\n  b:\n    c: 1\nd: 2\n",
+			want:  map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "1"}}, "d": "2"},
+		},
+		{
+			name:  "key without value",
+			input: "a:\nb: 1\nc:\n",
+			want:  map[string]interface{}{"a": "", "b": "1", "c": ""},
+		},
+		{
+			name:  "comments and blank lines",
+			input: "---\n# comment\n\na: 1 # trailing\n  # indented comment\nb: 2\n",
+			want:  map[string]interface{}{"a": "1", "b": "2"},
+		},
+		{
+			name:  "hash without space is part of the value",
+			input: "password: pa#ss\nurl: https://example.com/#frag\n",
+			want:  map[string]interface{}{"password": "pa#ss", "url": "https://example.com/#frag"},
+		},
+		{
+			name:  "colon in value",
+			input: "auth_url: https://auth.example.com:8443/token\n",
+			want:  map[string]interface{}{"auth_url": "https://auth.example.com:8443/token"},
+		},
+		{
+			name:  "double quoted",
+			input: `password: "a \"b\" # not a comment\n" # comment` + "\n",
+			want:  map[string]interface{}{"password": "a \"b\" # not a comment\n"},
+		},
+		{
+			name:  "single quoted",
+			input: "password: 'it''s # here' # comment\n",
+			want:  map[string]interface{}{"password": "it's # here"},
+		},
+		{
+			name:  "quoted keys",
+			input: "\"client id\": x\n'user': y\n",
+			want:  map[string]interface{}{"client id": "x", "user": "y"},
+		},
+		{
+			name:  "empty quoted value",
+			input: "password: \"\"\n",
+			want:  map[string]interface{}{"password": ""},
+		},
+		{
+			name:  "windows line endings",
+			input: "a: 1\r\nb:\r\n  c: 2\r\n",
+			want:  map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2"}},
+		},
+		{name: "tab indentation", input: "a:\n\tb: 1\n", wantErr: "line 2: tabs"},
+		{name: "inconsistent indentation", input: "a:\n  b: 1\n c: 2\n", wantErr: "line 3: inconsistent indentation"},
+		{name: "unexpected indentation", input: "a: 1\n  b: 2\n", wantErr: "line 2: inconsistent indentation"},
+		{name: "sequence", input: "a:\n  - b\n", wantErr: "line 2: expected"},
+		{name: "missing colon", input: "just text\n", wantErr: "line 1: expected"},
+		{name: "unterminated double quote", input: "a: \"b\n", wantErr: "line 1: unterminated double-quoted"},
+		{name: "unterminated single quote", input: "a: 'b\n", wantErr: "line 1: unterminated single-quoted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAMLMap(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseYAMLMap() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseYAMLMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAMLMap() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProfileFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantDefault string
+		want        map[string]Profile
+		wantErr     bool
+	}{
+		{
+			name: "profiles",
+			input: `default_profile: prod
+profiles:
+  prod:
+    client_id: id
+    client_secret: "s3cr#t" # quoted
+    username: api-user@example.com
+    password: 'pa''ss'
+    extra: ignored
+  staging:
+    API_USER: staging@example.com
+`,
+			wantDefault: "prod",
+			want: map[string]Profile{
+				"prod":    {ClientID: "id", ClientSecret: "s3cr#t", Username: "api-user@example.com", Password: "pa'ss"},
+				"staging": {Username: "staging@example.com"},
+			},
+		},
+		{
+			name: "cntb layout",
+			input: `oauth2-clientid: id
+oauth2-client-secret: secret
+oauth2-user: user@example.com
+oauth2-password: pass # comment
+oauth2-tokenurl: https://auth.example.com/token
+api: https://api.example.com
+`,
+			wantDefault: "default",
+			want: map[string]Profile{
+				"default": {ClientID: "id", ClientSecret: "secret", Username: "user@example.com", Password: "pass", AuthURL: "https://auth.example.com/token", BaseURL: "https://api.example.com"},
+			},
+		},
+		{name: "profiles not a mapping", input: "profiles: prod\n", wantErr: true},
+		{name: "profile not a mapping", input: "profiles:\n  prod: x\n", wantErr: true},
+		{name: "known key not a string", input: "profiles:\n  prod:\n    password:\n      nested: x\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfileFile(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProfileFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.DefaultProfile != tt.wantDefault {
+				t.Errorf("DefaultProfile = %q, want %q", got.DefaultProfile, tt.wantDefault)
+			}
+			if !reflect.DeepEqual(got.Profiles, tt.want) {
+				t.Errorf("Profiles = %+v, want %+v", got.Profiles, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package contabo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the client-side token-bucket rate limiter
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained rate for all requests (<= 0 disables limiting)
+	Burst             int     // Maximum number of requests sent without waiting (defaults to 1)
+
+	// Optional stricter limit applied additionally to POST, PUT, PATCH and DELETE
+	MutatingRequestsPerSecond float64
+	MutatingBurst             int
+}
+
+// RateLimiterStats reports how much time requests have spent waiting on the limiter
+type RateLimiterStats struct {
+	Requests        int64         // Requests that passed through the limiter
+	Waited          int64         // Requests that had to wait for a token
+	Waiting         int           // Requests currently waiting
+	TotalWait       time.Duration // Cumulative time spent waiting
+	LastWait        time.Duration // Wait time of the most recent request
+	MaxWait         time.Duration // Longest single wait observed
+	Canceled        int64         // Waits abandoned because the context was done
+	TokensAvailable float64       // Tokens currently available in the general bucket
+}
+
+// RateLimiter is a token-bucket limiter that is safe for concurrent use.
+// A single RateLimiter may be shared by several clients to enforce a common budget.
+type RateLimiter struct {
+	all      *tokenBucket
+	mutating *tokenBucket
+
+	mu    sync.Mutex
+	stats RateLimiterStats
+}
+
+// NewRateLimiter creates a rate limiter from the given configuration.
+// It returns nil if the configuration does not enable any limit.
+func NewRateLimiter(cfg *RateLimit) *RateLimiter {
+	if cfg == nil || (cfg.RequestsPerSecond <= 0 && cfg.MutatingRequestsPerSecond <= 0) {
+		return nil
+	}
+
+	return &RateLimiter{
+		all:      newTokenBucket(cfg.RequestsPerSecond, cfg.Burst),
+		mutating: newTokenBucket(cfg.MutatingRequestsPerSecond, cfg.MutatingBurst),
+	}
+}
+
+// Wait blocks until a request with the given method may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+
+	now := time.Now()
+	delay := l.all.reserve(now)
+	mutating := isMutating(method)
+	if mutating {
+		if d := l.mutating.reserve(now); d > delay {
+			delay = d
+		}
+	}
+
+	l.mu.Lock()
+	l.stats.Requests++
+	l.stats.LastWait = delay
+	if delay > 0 {
+		l.stats.Waited++
+		l.stats.Waiting++
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	err := sleepContext(ctx, delay)
+
+	l.mu.Lock()
+	l.stats.Waiting--
+	if err != nil {
+		l.stats.Canceled++
+	} else {
+		l.stats.TotalWait += delay
+		if delay > l.stats.MaxWait {
+			l.stats.MaxWait = delay
+		}
+	}
+	l.mu.Unlock()
+
+	if err != nil {
+		// Hand the unused reservation back so other callers are not penalised
+		l.all.cancel()
+		if mutating {
+			l.mutating.cancel()
+		}
+	}
+	return err
+}
+
+// Stats returns a snapshot of the limiter's wait statistics
+func (l *RateLimiter) Stats() RateLimiterStats {
+	if l == nil {
+		return RateLimiterStats{}
+	}
+
+	l.mu.Lock()
+	stats := l.stats
+	l.mu.Unlock()
+
+	stats.TokensAvailable = l.all.available(time.Now())
+	return stats
+}
+
+// isMutating reports whether the method changes server-side state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// tokenBucket is a minimal token bucket; a nil bucket never limits
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket, or nil if rate is not positive
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last update; callers hold b.mu
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// reserve takes one token and returns how long the caller must wait for it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a previously reserved token
+func (b *tokenBucket) cancel() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// available returns the number of tokens currently in the bucket
+func (b *tokenBucket) available(now time.Time) float64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	return b.tokens
+}
@@ -0,0 +1,119 @@
+package contabo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		at    []time.Duration // Offsets of the reservations from the bucket's creation
+		want  []time.Duration
+	}{
+		{"burst then wait", 10, 2, []time.Duration{0, 0, 0, 0}, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}},
+		{"burst defaults to one", 2, 0, []time.Duration{0, 0}, []time.Duration{0, 500 * time.Millisecond}},
+		{"refill", 10, 1, []time.Duration{0, 0, 250 * time.Millisecond}, []time.Duration{0, 100 * time.Millisecond, 0}},
+		{"refill capped at burst", 10, 2, []time.Duration{0, 0, 10 * time.Second, 10 * time.Second, 10 * time.Second}, []time.Duration{0, 0, 0, 0, 100 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(tt.rate, tt.burst)
+			start := b.last
+			for i, offset := range tt.at {
+				if got := b.reserve(start.Add(offset)); !closeTo(got, tt.want[i]) {
+					t.Errorf("reservation %d: wait = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenBucketDisabled(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		if b := newTokenBucket(rate, 5); b != nil || b.reserve(time.Now()) != 0 {
+			t.Errorf("newTokenBucket(%v) = %v, want a nil bucket that never limits", rate, b)
+		}
+	}
+	if NewRateLimiter(&RateLimit{}) != nil {
+		t.Errorf("NewRateLimiter(zero config) != nil")
+	}
+}
+
+func TestRateLimiterCancelReturnsReservation(t *testing.T) {
+	tests := []struct {
+		method   string
+		mutating bool
+	}{
+		{http.MethodGet, false},
+		{http.MethodPost, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			l := NewRateLimiter(&RateLimit{RequestsPerSecond: 0.001, Burst: 1, MutatingRequestsPerSecond: 0.001, MutatingBurst: 1})
+
+			// Take the only tokens so the next request has to wait
+			if err := l.Wait(context.Background(), tt.method); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if err := l.Wait(ctx, tt.method); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+			}
+
+			// Without the cancelled reservation being returned the buckets would be at -1
+			if got := l.all.available(time.Now()); !closeToTokens(got, 0) {
+				t.Errorf("general tokens = %v, want 0", got)
+			}
+			if tt.mutating {
+				if got := l.mutating.available(time.Now()); !closeToTokens(got, 0) {
+					t.Errorf("mutating tokens = %v, want 0", got)
+				}
+			}
+
+			stats := l.Stats()
+			if stats.Requests != 2 || stats.Waited != 1 || stats.Canceled != 1 || stats.Waiting != 0 || stats.TotalWait != 0 {
+				t.Errorf("Stats() = %+v, want 2 requests, 1 waited and cancelled, none waiting", stats)
+			}
+		})
+	}
+}
+
+func TestRateLimiterMutatingLimit(t *testing.T) {
+	l := NewRateLimiter(&RateLimit{MutatingRequestsPerSecond: 0.001, MutatingBurst: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx, http.MethodDelete); err != nil {
+		t.Fatalf("first DELETE: Wait() error = %v", err)
+	}
+	for range 5 {
+		if err := l.Wait(ctx, http.MethodGet); err != nil {
+			t.Fatalf("GET: Wait() error = %v, want no limit on reads", err)
+		}
+	}
+	if err := l.Wait(ctx, http.MethodDelete); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second DELETE: Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// closeTo allows for floating point rounding of computed waits
+func closeTo(got, want time.Duration) bool {
+	d := got - want
+	return d > -time.Microsecond && d < time.Microsecond
+}
+
+// closeToTokens allows for the tokens refilled while the test runs
+func closeToTokens(got, want float64) bool {
+	return got >= want-1e-6 && got < want+0.01
+}
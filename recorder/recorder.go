@@ -0,0 +1,263 @@
+// Package recorder provides an http.RoundTripper that records HTTP
+// interactions to cassette files and replays them, for deterministic tests
+// of code using the SDK without access to the Contabo API.
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette and never contacts the network
+	ModeReplay Mode = iota
+	// ModeRecord sends requests and records them, replacing the cassette on Stop
+	ModeRecord
+	// ModeReplayOrRecord replays an existing cassette and records a new one otherwise
+	ModeReplayOrRecord
+)
+
+// ErrNoMatch is returned in replay mode for a request not found in the cassette
+var ErrNoMatch = errors.New("recorder: no matching interaction in cassette")
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to send requests in record mode
+// (defaults to http.DefaultTransport)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubber adds a function that removes further secrets from each
+// interaction before it is saved. In replay mode it is applied to incoming
+// requests, with an empty Response, so they match the scrubbed cassette.
+func WithScrubber(scrub func(*Interaction)) Option {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, scrub)
+	}
+}
+
+// Recorder is an http.RoundTripper recording to or replaying from a cassette.
+//
+// Requests are matched by method, path, query and body; headers, including
+// x-request-id and Authorization, and the host are ignored. Secrets are
+// scrubbed from both the cassette and incoming requests before matching, so
+// replays work with any credentials. Interactions are replayed in recorded
+// order; once all matching interactions were used, the last one is repeated.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubbers []func(*Interaction)
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	used      []bool
+	unmatched []string
+}
+
+// New creates a recorder for the cassette at path
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+		r.cassette = &Cassette{}
+	case ModeReplay, ModeReplayOrRecord:
+		cassette, err := LoadCassette(path)
+		switch {
+		case err == nil:
+			r.cassette = cassette
+			r.mode = ModeReplay
+		case errors.Is(err, fs.ErrNotExist) && mode == ModeReplayOrRecord:
+			r.cassette = &Cassette{}
+			r.mode = ModeRecord
+		default:
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %d", mode)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// Mode returns whether the recorder is recording or replaying
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client using the recorder as its transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: scrubHeader(req.Header),
+		Body:   scrubBody(body, req.Header.Get("Content-Type")),
+	}
+	// Request IDs differ on every run and would only add noise to cassettes
+	recorded.Header.Del("X-Request-Id")
+
+	if r.mode == ModeReplay {
+		incoming := &Interaction{Request: recorded}
+		for _, scrub := range r.scrubbers {
+			scrub(incoming)
+		}
+		return r.replay(req, incoming.Request)
+	}
+	return r.record(req, recorded)
+}
+
+// replay serves req from the cassette
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	key := matchKey(recorded)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		if matchKey(interaction.Request) != key {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		msg := fmt.Sprintf("%s %s", req.Method, req.URL.RequestURI())
+		if recorded.Body != "" {
+			msg += " with body " + recorded.Body
+		}
+		r.unmatched = append(r.unmatched, msg)
+		return nil, fmt.Errorf("%w %s: %s", ErrNoMatch, r.path, msg)
+	}
+	r.used[found] = true
+
+	return newResponse(req, r.cassette.Interactions[found].Response), nil
+}
+
+// record sends req and appends the interaction to the cassette
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+			Body:       scrubBody(string(respBody), resp.Header.Get("Content-Type")),
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(interaction)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	// The caller gets the real response, only the cassette is scrubbed
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// Stop saves the cassette in record mode. In replay mode it reports the
+// requests that had no matching interaction, even if the code under test
+// ignored the errors.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeRecord {
+		return r.cassette.Save(r.path)
+	}
+	if len(r.unmatched) > 0 {
+		return fmt.Errorf("%w %s: %d unmatched requests:\n  %s",
+			ErrNoMatch, r.path, len(r.unmatched), strings.Join(r.unmatched, "\n  "))
+	}
+	return nil
+}
+
+// readBody reads the request body and restores it for sending
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// matchKey identifies a request by method, path, query and body
+func matchKey(req Request) string {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return req.Method + " " + req.URL + "\n" + req.Body
+	}
+	return req.Method + " " + u.Path + "?" + u.Query().Encode() + "\n" + req.Body
+}
+
+// newResponse builds the response of a replayed interaction
+func newResponse(req *http.Request, recorded Response) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,171 @@
+package recorder_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/dns"
+	"github.com/mithucste30/contabo-api-golang/recorder"
+	"github.com/mithucste30/contabo-api-golang/secret"
+)
+
+// replayConfig returns a config for the real API, which replays never
+// contact, with other credentials than those used when recording. Retries
+// are disabled, as an unmatched request would otherwise be retried.
+func replayConfig() *contabo.Config {
+	config := contabo.NewConfig("other-client", "other-secret", "other@example.com", "other-password")
+	config.RetryPolicy = contabo.NoRetryPolicy()
+	return config
+}
+
+// exercise makes the calls recorded and replayed by the tests
+func exercise(t *testing.T, sdk *contabo.SDK) (*secret.Secret, []dns.Zone) {
+	t.Helper()
+	ctx := context.Background()
+
+	created, err := sdk.Secret.CreateSecret(ctx, &secret.CreateSecretRequest{
+		Name:  "deploy",
+		Type:  secret.TypePassword,
+		Value: "s3cr3t-Passw0rd!",
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	zones, err := sdk.DNS.ListZones(ctx, &dns.ListOptions{Size: 10})
+	if err != nil {
+		t.Fatalf("ListZones() error = %v", err)
+	}
+	return created, zones.Data
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	// Record against the fake API
+	server := contabotest.NewServer()
+	server.AddZone(dns.Zone{Name: "example.com"})
+	rec, err := recorder.New(path, recorder.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeRecord {
+		t.Fatalf("Mode() = %v without a cassette, want ModeRecord", rec.Mode())
+	}
+	sdk, err := server.NewSDK(contabo.WithTransport(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedSecret, recordedZones := exercise(t, sdk)
+	if recordedSecret.Value != "s3cr3t-Passw0rd!" {
+		t.Errorf("recorded call returned value %q, want the real response", recordedSecret.Value)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cassette not saved: %v", err)
+	}
+	for _, leaked := range []string{"s3cr3t-Passw0rd!", contabotest.Password, contabotest.ClientSecret, "Bearer "} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("cassette contains %q", leaked)
+		}
+	}
+	cassette, err := recorder.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cassette.Interactions); n != 3 {
+		t.Errorf("cassette holds %d interactions, want the token call and two API calls", n)
+	}
+
+	// Replay without a server, with other credentials and host
+	rec, err = recorder.New(path, recorder.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeReplay {
+		t.Fatalf("Mode() = %v with a cassette, want ModeReplay", rec.Mode())
+	}
+	sdk, err = contabo.NewSDK(replayConfig(), contabo.WithTransport(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedSecret, replayedZones := exercise(t, sdk)
+	if replayedSecret.SecretID != recordedSecret.SecretID || replayedSecret.Name != "deploy" {
+		t.Errorf("replayed secret %+v, want %+v", replayedSecret, recordedSecret)
+	}
+	if replayedSecret.Value != recorder.Redacted {
+		t.Errorf("replayed secret value %q, want %q", replayedSecret.Value, recorder.Redacted)
+	}
+	if len(replayedZones) != len(recordedZones) || replayedZones[0].Name != "example.com" {
+		t.Errorf("replayed zones %+v, want %+v", replayedZones, recordedZones)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop() error = %v after a complete replay", err)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := contabotest.NewServer()
+	rec, err := recorder.New(path, recorder.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdk, err := server.NewSDK(contabo.WithTransport(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.DNS.ListZones(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	rec, err = recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdk, err = contabo.NewSDK(replayConfig(), contabo.WithTransport(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Recorded without options, so the query does not match
+	_, err = sdk.DNS.ListZones(context.Background(), &dns.ListOptions{Page: 2})
+	if !errors.Is(err, recorder.ErrNoMatch) {
+		t.Fatalf("ListZones() error = %v, want ErrNoMatch", err)
+	}
+	// An unrecorded body does not match either, even if the error is ignored
+	sdk.Secret.CreateSecret(context.Background(), &secret.CreateSecretRequest{Name: "unrecorded", Type: secret.TypePassword, Value: "x"})
+
+	err = rec.Stop()
+	if !errors.Is(err, recorder.ErrNoMatch) {
+		t.Fatalf("Stop() error = %v, want ErrNoMatch", err)
+	}
+	msg := err.Error()
+	for _, want := range []string{"2 unmatched requests", "GET /v1/dns/zones?page=2", "POST /v1/secrets with body", `"value":"[REDACTED]"`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Stop() error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	_, err := recorder.New(filepath.Join(t.TempDir(), "missing.json"), recorder.ModeReplay)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New() error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,97 @@
+package recorder
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces scrubbed values in cassettes
+const Redacted = "[REDACTED]"
+
+// secretKeys are JSON and form fields whose values are scrubbed, compared
+// case-insensitively
+var secretKeys = map[string]bool{
+	"client_id":     true,
+	"client_secret": true,
+	"username":      true,
+	"password":      true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"accesskey":     true, // S3 credentials
+	"secretkey":     true,
+	"value":         true, // Secret values (SSH keys and passwords)
+}
+
+// secretHeaders are headers removed from cassettes
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// scrubHeader returns a copy of h without secret headers
+func scrubHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	h = h.Clone()
+	for _, key := range secretHeaders {
+		if _, ok := h[key]; ok {
+			h.Set(key, Redacted)
+		}
+	}
+	return h
+}
+
+// scrubBody redacts secret fields of a JSON or form-encoded body. Other
+// bodies are returned unchanged.
+func scrubBody(body, contentType string) string {
+	if body == "" {
+		return ""
+	}
+
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			out, err := json.Marshal(scrubJSON(v))
+			if err == nil {
+				return string(out)
+			}
+		}
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(body)
+		if err == nil {
+			for key := range values {
+				if secretKeys[strings.ToLower(key)] {
+					values.Set(key, Redacted)
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	return body
+}
+
+// scrubJSON walks a decoded JSON value and redacts secret fields
+func scrubJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if secretKeys[strings.ToLower(key)] {
+				v[key] = Redacted
+			} else {
+				v[key] = scrubJSON(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = scrubJSON(value)
+		}
+	}
+	return v
+}
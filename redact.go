@@ -0,0 +1,94 @@
+package contabo
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// redacted replaces sensitive values in log output
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are JSON keys and headers whose values are never logged,
+// compared case-insensitively
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"client_secret": true,
+	"clientsecret":  true,
+	"password":      true,
+	"access_token":  true,
+	"refresh_token": true,
+	"secretkey":     true, // S3 credentials
+	"value":         true, // Secret values (SSH keys and passwords)
+}
+
+// isSensitive reports whether values under key must be redacted
+func isSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// redactJSON returns body with all sensitive fields redacted. Bodies that are
+// not JSON, such as HTML error pages, are returned unchanged; malformed or
+// truncated JSON is replaced entirely, as its fields cannot be inspected.
+func redactJSON(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		if looksLikeJSON(body) {
+			return redacted
+		}
+		return string(body)
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(out)
+}
+
+// looksLikeJSON reports whether body starts like a JSON object or array
+func looksLikeJSON(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// redactValue walks a decoded JSON value and redacts sensitive fields
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// redactHeader returns a copy of h suitable for logging
+func redactHeader(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for key, values := range h {
+		if isSensitive(key) {
+			out[key] = redacted
+		} else {
+			out[key] = strings.Join(values, ", ")
+		}
+	}
+	return out
+}
+
+// discardLogger is used when no logger is configured
+var discardLogger = slog.New(slog.DiscardHandler)
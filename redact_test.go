@@ -0,0 +1,112 @@
+package contabo
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string // JSON is compared semantically, other bodies exactly
+	}{
+		{"empty", "", ""},
+		{
+			name: "credentials",
+			body: `{"client_id":"id","client_secret":"s3cret","username":"user@example.com","password":"hunter2"}`,
+			want: `{"client_id":"id","client_secret":"[REDACTED]","username":"user@example.com","password":"[REDACTED]"}`,
+		},
+		{
+			name: "keys compared case-insensitively",
+			body: `{"Password":"a","clientSecret":"b","Authorization":"Bearer c"}`,
+			want: `{"Password":"[REDACTED]","clientSecret":"[REDACTED]","Authorization":"[REDACTED]"}`,
+		},
+		{
+			name: "tokens",
+			body: `{"access_token":"a","refresh_token":"r","token_type":"Bearer"}`,
+			want: `{"access_token":"[REDACTED]","refresh_token":"[REDACTED]","token_type":"Bearer"}`,
+		},
+		{
+			name: "nested secret value",
+			body: `{"data":{"secretId":1,"name":"deploy","type":"ssh","value":"ssh-ed25519 AAAA"}}`,
+			want: `{"data":{"secretId":1,"name":"deploy","type":"ssh","value":"[REDACTED]"}}`,
+		},
+		{
+			name: "secrets in an array",
+			body: `{"data":[{"name":"a","value":"one"},{"name":"b","value":"two"}],"_pagination":{"size":2}}`,
+			want: `{"data":[{"name":"a","value":"[REDACTED]"},{"name":"b","value":"[REDACTED]"}],"_pagination":{"size":2}}`,
+		},
+		{
+			name: "S3 credentials",
+			body: `{"data":[{"accessKey":"AKIA","secretKey":"wJalr"}]}`,
+			want: `{"data":[{"accessKey":"AKIA","secretKey":"[REDACTED]"}]}`,
+		},
+		{
+			name: "top-level array",
+			body: `[{"password":"x"},["nested",{"value":"y"}]]`,
+			want: `[{"password":"[REDACTED]"},["nested",{"value":"[REDACTED]"}]]`,
+		},
+		{
+			name: "sensitive object replaced as a whole",
+			body: `{"password":{"old":"a","new":"b"}}`,
+			want: `{"password":"[REDACTED]"}`,
+		},
+		{
+			name: "nothing sensitive",
+			body: `{"displayName":"web","instanceIds":[1,2]}`,
+			want: `{"displayName":"web","instanceIds":[1,2]}`,
+		},
+		{"HTML passes through", "<html><body>502 Bad Gateway</body></html>", "<html><body>502 Bad Gateway</body></html>"},
+		{"plain text passes through", "Too Many Requests", "Too Many Requests"},
+		{"truncated JSON replaced", `{"data":[{"value":"ssh-ed25519 AA`, redacted},
+		{"malformed JSON array replaced", ` [{"password":"x"`, redacted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactJSON([]byte(tt.body))
+
+			var gotValue, wantValue interface{}
+			if json.Unmarshal([]byte(tt.want), &wantValue) != nil {
+				if got != tt.want {
+					t.Errorf("redactJSON() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if err := json.Unmarshal([]byte(got), &gotValue); err != nil {
+				t.Fatalf("redactJSON() = %q, not valid JSON: %v", got, err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("redactJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer eyJhbGciOi")
+	h.Set("X-Request-Id", "d2c1b6a0")
+	h.Set("Content-Type", "application/json")
+	h.Add("Accept", "application/json")
+	h.Add("Accept", "text/plain")
+	h["password"] = []string{"hunter2"} // Non-canonical keys are matched too
+
+	got := redactHeader(h)
+	want := map[string]string{
+		"Authorization": redacted,
+		"X-Request-Id":  "d2c1b6a0",
+		"Content-Type":  "application/json",
+		"Accept":        "application/json, text/plain",
+		"password":      redacted,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactHeader() = %v, want %v", got, want)
+	}
+	if h.Get("Authorization") != "Bearer eyJhbGciOi" {
+		t.Error("redactHeader() modified the request headers")
+	}
+}
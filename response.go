@@ -0,0 +1,122 @@
+package contabo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response holds the metadata of a completed API call
+type Response struct {
+	StatusCode int         // HTTP status, 0 if no response was received
+	Header     http.Header // Response headers, nil if no response was received
+	RequestID  string      // x-request-id sent with the call
+	TraceID    string      // x-trace-id sent with the call, if any
+	Duration   time.Duration
+
+	// Rate limit state reported by the API; -1 and the zero time if the
+	// response had no rate limit headers
+	RateLimitLimit     int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+
+	Err error // Error returned by the call, if any
+}
+
+// WithResponseHook returns a context that calls fn with the metadata of every
+// API call made with it, including failed calls. Hooks of parent contexts are
+// still called. Calls made concurrently with the context, e.g. by ListAll
+// methods with ListOptions.Concurrency, call fn concurrently, so it must be
+// safe for concurrent use.
+func WithResponseHook(ctx context.Context, fn func(*Response)) context.Context {
+	if parent, ok := ctx.Value(responseHookKey).(func(*Response)); ok {
+		next := fn
+		fn = func(r *Response) {
+			parent(r)
+			next(r)
+		}
+	}
+	return context.WithValue(ctx, responseHookKey, fn)
+}
+
+// WithResponse returns a context that stores the metadata of the API calls
+// made with it in resp. Service methods that make several calls leave the
+// metadata of the last one to complete. Concurrent calls are serialised, so
+// resp can be read once the service method has returned.
+//
+//	var resp contabo.Response
+//	instance, err := sdk.Compute.GetInstance(contabo.WithResponse(ctx, &resp), id)
+//	fmt.Println(resp.RequestID, resp.RateLimitRemaining)
+func WithResponse(ctx context.Context, resp *Response) context.Context {
+	var mu sync.Mutex
+	return WithResponseHook(ctx, func(r *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		*resp = *r
+	})
+}
+
+// observeResponse reports the metadata of a completed API call to the
+// context's response hook
+func observeResponse(ctx context.Context, resp *http.Response, err error, duration time.Duration) {
+	hook, ok := ctx.Value(responseHookKey).(func(*Response))
+	if !ok {
+		return
+	}
+
+	r := &Response{
+		StatusCode:         callStatusCode(resp, err),
+		Duration:           duration,
+		RateLimitLimit:     -1,
+		RateLimitRemaining: -1,
+		Err:                err,
+	}
+	r.RequestID, _ = RequestIDFromContext(ctx)
+	r.TraceID, _ = TraceIDFromContext(ctx)
+
+	if resp != nil {
+		r.Header = resp.Header
+		// The sent request has the final IDs, including a trace ID derived by the tracer
+		if req := resp.Request; req != nil {
+			if id := req.Header.Get("x-request-id"); id != "" {
+				r.RequestID = id
+			}
+			if id := req.Header.Get("x-trace-id"); id != "" {
+				r.TraceID = id
+			}
+		}
+		r.RateLimitLimit = headerInt(resp.Header, "X-RateLimit-Limit", "RateLimit-Limit")
+		r.RateLimitRemaining = headerInt(resp.Header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+		r.RateLimitReset = rateLimitReset(resp.Header, time.Now())
+	}
+
+	hook(r)
+}
+
+// headerInt returns the first of the headers that holds an integer, or -1
+func headerInt(h http.Header, keys ...string) int {
+	for _, key := range keys {
+		if n, err := strconv.Atoi(strings.TrimSpace(h.Get(key))); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
+// rateLimitReset returns when the rate limit window resets. The header holds
+// either the seconds until the reset or, for large values, a Unix timestamp.
+func rateLimitReset(h http.Header, now time.Time) time.Time {
+	n := headerInt(h, "X-RateLimit-Reset", "RateLimit-Reset")
+	switch {
+	case n < 0:
+		return time.Time{}
+	case n > 1_000_000_000:
+		return time.Unix(int64(n), 0)
+	default:
+		return now.Add(time.Duration(n) * time.Second)
+	}
+}
@@ -0,0 +1,56 @@
+package contabo_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/compute"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+)
+
+func TestWithResponse(t *testing.T) {
+	sdk, server := contabotest.NewSDK(t)
+	instance := server.AddInstance(compute.Instance{DisplayName: "web-1"})
+
+	var resp contabo.Response
+	if _, err := sdk.Compute.GetInstance(contabo.WithResponse(context.Background(), &resp), instance.InstanceID); err != nil {
+		t.Fatalf("GetInstance() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.RequestID == "" || resp.Err != nil {
+		t.Errorf("resp = %+v, want status 200 with a request ID", resp)
+	}
+
+	_, err := sdk.Compute.GetInstance(contabo.WithResponse(context.Background(), &resp), instance.InstanceID+1)
+	if err == nil || resp.StatusCode != http.StatusNotFound || resp.Err == nil {
+		t.Errorf("resp = %+v, err = %v, want status 404 with the error", resp, err)
+	}
+}
+
+// Run with -race: ListAll with Concurrency calls the hooks from several goroutines
+func TestWithResponseConcurrent(t *testing.T) {
+	sdk, server := contabotest.NewSDK(t)
+	for range 20 {
+		server.AddInstance(compute.Instance{})
+	}
+
+	var resp contabo.Response
+	var calls atomic.Int64
+	ctx := contabo.WithResponse(context.Background(), &resp)
+	ctx = contabo.WithResponseHook(ctx, func(*contabo.Response) { calls.Add(1) })
+
+	instances, err := sdk.Compute.ListAllInstances(ctx, &compute.ListInstancesOptions{
+		ListOptions: compute.ListOptions{Size: 2, Concurrency: 4},
+	})
+	if err != nil {
+		t.Fatalf("ListAllInstances() error = %v", err)
+	}
+	if len(instances) != 20 {
+		t.Errorf("got %d instances, want 20", len(instances))
+	}
+	if calls.Load() != 10 || resp.StatusCode != http.StatusOK {
+		t.Errorf("hook calls = %d, last status = %d, want 10 calls with status 200", calls.Load(), resp.StatusCode)
+	}
+}
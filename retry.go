@@ -0,0 +1,137 @@
+package contabo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries in Client.Do
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one (<= 1 disables retries)
+	BaseBackoff time.Duration // Delay before the first retry, doubled for each further retry
+	MaxBackoff  time.Duration // Upper bound for a single delay, including Retry-After
+	Jitter      float64       // Fraction (0-1) of the delay that is randomised
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// NoRetryPolicy returns a policy that performs exactly one attempt
+func NoRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 1}
+}
+
+// isIdempotent reports whether a request with the given method may be safely repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry decides whether an attempt that produced resp or err should be retried.
+// POST and PATCH are retried only when the connection was never established, or
+// on a 429/503 with Retry-After: the server then declined the request without
+// processing it, so a retry cannot apply it twice. Other failures of those
+// methods may follow a request that was applied and are returned as they are.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// A connection that was never established is safe to retry for any method
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The server explicitly asked us to come back later
+		return isIdempotent(req.Method) || resp.Header.Get("Retry-After") != ""
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1 for the first retry)
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.BaseBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		jitter := time.Duration(float64(d) * p.Jitter * (rand.Float64()*2 - 1))
+		d += jitter
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,303 @@
+package contabo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://api.contabo.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "https://api.contabo.com", Err: &net.OpError{Op: "read", Err: errors.New("connection reset")}}
+
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		err        error
+		want       bool
+	}{
+		{"GET 429", http.MethodGet, http.StatusTooManyRequests, "", nil, true},
+		{"GET 500", http.MethodGet, http.StatusInternalServerError, "", nil, true},
+		{"GET 502", http.MethodGet, http.StatusBadGateway, "", nil, true},
+		{"GET 503", http.MethodGet, http.StatusServiceUnavailable, "", nil, true},
+		{"GET 504", http.MethodGet, http.StatusGatewayTimeout, "", nil, true},
+		{"GET 200", http.MethodGet, http.StatusOK, "", nil, false},
+		{"GET 400", http.MethodGet, http.StatusBadRequest, "", nil, false},
+		{"GET 401", http.MethodGet, http.StatusUnauthorized, "", nil, false},
+		{"GET 404", http.MethodGet, http.StatusNotFound, "", nil, false},
+		{"GET 501", http.MethodGet, http.StatusNotImplemented, "", nil, false},
+		{"HEAD 503", http.MethodHead, http.StatusServiceUnavailable, "", nil, true},
+		{"PUT 500", http.MethodPut, http.StatusInternalServerError, "", nil, true},
+		{"DELETE 502", http.MethodDelete, http.StatusBadGateway, "", nil, true},
+		{"POST 500", http.MethodPost, http.StatusInternalServerError, "", nil, false},
+		{"POST 502", http.MethodPost, http.StatusBadGateway, "", nil, false},
+		{"POST 504", http.MethodPost, http.StatusGatewayTimeout, "", nil, false},
+		{"POST 503 without Retry-After", http.MethodPost, http.StatusServiceUnavailable, "", nil, false},
+		{"POST 429 without Retry-After", http.MethodPost, http.StatusTooManyRequests, "", nil, false},
+		{"POST 503 with Retry-After", http.MethodPost, http.StatusServiceUnavailable, "2", nil, true},
+		{"POST 429 with Retry-After", http.MethodPost, http.StatusTooManyRequests, "2", nil, true},
+		{"PATCH 429 with Retry-After", http.MethodPatch, http.StatusTooManyRequests, "2", nil, true},
+		{"POST 500 with Retry-After", http.MethodPost, http.StatusInternalServerError, "2", nil, false},
+		{"GET dial error", http.MethodGet, 0, "", dialErr, true},
+		{"POST dial error", http.MethodPost, 0, "", dialErr, true},
+		{"GET read error", http.MethodGet, 0, "", readErr, true},
+		{"POST read error", http.MethodPost, 0, "", readErr, false},
+		{"PATCH unexpected EOF", http.MethodPatch, 0, "", io.ErrUnexpectedEOF, false},
+		{"GET cancelled", http.MethodGet, 0, "", fmt.Errorf("get: %w", context.Canceled), false},
+		{"GET deadline exceeded", http.MethodGet, 0, "", &url.Error{Op: "Get", Err: context.DeadlineExceeded}, false},
+	}
+
+	policy := DefaultRetryPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "https://api.contabo.com/v1/compute/instances", nil)
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status, Header: http.Header{}}
+				if tt.retryAfter != "" {
+					resp.Header.Set("Retry-After", tt.retryAfter)
+				}
+			}
+			if got := policy.shouldRetry(req, resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   []time.Duration // Delays before retries 1, 2, 3, ...
+	}{
+		{
+			name:   "doubles per retry",
+			policy: RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Minute},
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond},
+		},
+		{
+			name:   "capped at MaxBackoff",
+			policy: RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond},
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond},
+		},
+		{
+			name:   "no MaxBackoff",
+			policy: RetryPolicy{BaseBackoff: time.Second},
+			want:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:   "no BaseBackoff",
+			policy: RetryPolicy{MaxBackoff: time.Second, Jitter: 0.5},
+			want:   []time.Duration{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Duration
+			for retry := 1; retry <= len(tt.want); retry++ {
+				got = append(got, tt.policy.backoff(retry, nil))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("delays = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: 400 * time.Millisecond, Jitter: 0.2}
+
+	for retry, base := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: 400 * time.Millisecond} {
+		low, high := base*8/10, base*12/10
+		varied := false
+		for range 200 {
+			d := policy.backoff(retry, nil)
+			if d < low || d > high {
+				t.Fatalf("retry %d: delay %v outside [%v, %v]", retry, d, low, high)
+			}
+			varied = varied || d != base
+		}
+		if !varied {
+			t.Errorf("retry %d: every delay was exactly %v, want jitter", retry, base)
+		}
+	}
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"seconds on 503", http.StatusServiceUnavailable, "3", 3 * time.Second},
+		{"seconds on 429", http.StatusTooManyRequests, "3", 3 * time.Second},
+		{"zero seconds", http.StatusTooManyRequests, "0", 0},
+		{"capped at MaxBackoff", http.StatusTooManyRequests, "3600", 10 * time.Second},
+		{"HTTP date in the past", http.StatusServiceUnavailable, "Wed, 21 Oct 2015 07:28:00 GMT", 0},
+		{"malformed falls back to exponential backoff", http.StatusServiceUnavailable, "soon", 2 * time.Second},
+		{"missing falls back to exponential backoff", http.StatusTooManyRequests, "", 2 * time.Second},
+		{"ignored on 500", http.StatusInternalServerError, "3", 2 * time.Second},
+	}
+
+	policy := RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := policy.backoff(2, resp); got != tt.want {
+				t.Errorf("backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"empty", "", 0, false},
+		{"zero seconds", "0", 0, true},
+		{"seconds", "120", 2 * time.Minute, true},
+		{"negative seconds", "-1", 0, false},
+		{"fractional seconds", "1.5", 0, false},
+		{"text", "later", 0, false},
+		{"HTTP date in the past", "Sun, 06 Nov 1994 08:49:37 GMT", 0, true},
+		{"RFC 850 date in the past", "Sunday, 06-Nov-94 08:49:37 GMT", 0, true},
+		{"date without time zone", "2015-10-21 07:28:00", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("HTTP date in the future", func(t *testing.T) {
+		value := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+		got, ok := parseRetryAfter(value)
+		// HTTP dates have a resolution of one second
+		if !ok || got < 88*time.Second || got > 90*time.Second {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want about 90s", value, got, ok)
+		}
+	})
+}
+
+// attempt is a request received by the retry test server
+type attempt struct {
+	requestID string
+	body      string
+}
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statuses     []int // Responses to successive attempts; 200 once exhausted
+		retryAfter   string
+		wantAttempts int
+		wantStatus   int // Status of the returned APIError, 0 for success
+	}{
+		{"GET succeeds after 503s", http.MethodGet, []int{503, 503}, "", 3, 0},
+		{"GET gives up after MaxAttempts", http.MethodGet, []int{502, 502, 502, 502}, "", 3, 502},
+		{"PUT retried on 500", http.MethodPut, []int{500}, "", 2, 0},
+		{"POST not retried on 500", http.MethodPost, []int{500}, "", 1, 500},
+		{"POST not retried on 503 without Retry-After", http.MethodPost, []int{503}, "", 1, 503},
+		{"POST retried on 503 with Retry-After", http.MethodPost, []int{503}, "0", 2, 0},
+		{"PATCH retried on 429 with Retry-After", http.MethodPatch, []int{429, 429}, "0", 3, 0},
+		{"GET not retried on 404", http.MethodGet, []int{404}, "", 1, 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				attempts []attempt
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				attempts = append(attempts, attempt{requestID: r.Header.Get("x-request-id"), body: string(body)})
+				n := len(attempts)
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				if n <= len(tt.statuses) {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.statuses[n-1])
+					fmt.Fprint(w, `{"message":"try again"}`)
+					return
+				}
+				fmt.Fprint(w, `{}`)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(&Config{
+				BaseURL:     server.URL,
+				TokenSource: StaticTokenSource("token"),
+				RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var payload interface{}
+			if tt.method != http.MethodGet {
+				payload = map[string]string{"name": "web", "comment": "retried"}
+			}
+			_, err = client.call(context.Background(), tt.method, "/v1/compute/instances/1", payload, nil)
+
+			var apiErr *APIError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("error = %v, want success", err)
+			case tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus):
+				t.Fatalf("error = %v, want an APIError with status %d", err, tt.wantStatus)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(attempts) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(attempts), tt.wantAttempts)
+			}
+			first := attempts[0]
+			if first.requestID == "" {
+				t.Error("request sent without x-request-id")
+			}
+			if tt.method != http.MethodGet && first.body == "" {
+				t.Error("request sent without a body")
+			}
+			for i, a := range attempts[1:] {
+				if a.requestID != first.requestID {
+					t.Errorf("attempt %d x-request-id = %q, want %q", i+2, a.requestID, first.requestID)
+				}
+				if a.body != first.body {
+					t.Errorf("attempt %d body = %q, want %q", i+2, a.body, first.body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package contabo
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Route describes a Contabo API endpoint called by the SDK
+type Route struct {
+	Method       string
+	PathTemplate string // e.g. "/v1/compute/instances/{instanceId}"
+	Service      string // Service package, e.g. "compute"
+	Operation    string // Service method, e.g. "GetInstance"
+}
+
+// routes lists every endpoint used by the service packages
+var routes = []Route{
+	// Compute instances
+	{"GET", "/v1/compute/instances", "compute", "ListInstances"},
+	{"POST", "/v1/compute/instances", "compute", "CreateInstance"},
+	{"GET", "/v1/compute/instances/{instanceId}", "compute", "GetInstance"},
+	{"PATCH", "/v1/compute/instances/{instanceId}", "compute", "UpdateInstance"},
+	{"PUT", "/v1/compute/instances/{instanceId}", "compute", "ReinstallInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/cancel", "compute", "CancelInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/upgrade", "compute", "UpgradeInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/start", "compute", "StartInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/stop", "compute", "StopInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/restart", "compute", "RestartInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/shutdown", "compute", "ShutdownInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/rescue", "compute", "RescueInstance"},
+	{"POST", "/v1/compute/instances/{instanceId}/actions/resetPassword", "compute", "ResetPassword"},
+
+	// Compute snapshots
+	{"GET", "/v1/compute/instances/{instanceId}/snapshots", "compute", "ListSnapshots"},
+	{"POST", "/v1/compute/instances/{instanceId}/snapshots", "compute", "CreateSnapshot"},
+	{"GET", "/v1/compute/instances/{instanceId}/snapshots/{snapshotId}", "compute", "GetSnapshot"},
+	{"PATCH", "/v1/compute/instances/{instanceId}/snapshots/{snapshotId}", "compute", "UpdateSnapshot"},
+	{"DELETE", "/v1/compute/instances/{instanceId}/snapshots/{snapshotId}", "compute", "DeleteSnapshot"},
+	{"POST", "/v1/compute/instances/{instanceId}/snapshots/{snapshotId}/rollback", "compute", "RollbackSnapshot"},
+
+	// Compute images
+	{"GET", "/v1/compute/images", "compute", "ListImages"},
+	{"POST", "/v1/compute/images", "compute", "CreateImage"},
+	{"GET", "/v1/compute/images/{imageId}", "compute", "GetImage"},
+	{"PATCH", "/v1/compute/images/{imageId}", "compute", "UpdateImage"},
+	{"DELETE", "/v1/compute/images/{imageId}", "compute", "DeleteImage"},
+
+	// Object storage
+	{"GET", "/v1/object-storages", "storage", "ListObjectStorages"},
+	{"POST", "/v1/object-storages", "storage", "CreateObjectStorage"},
+	{"GET", "/v1/object-storages/{objectStorageId}", "storage", "GetObjectStorage"},
+	{"PATCH", "/v1/object-storages/{objectStorageId}", "storage", "UpdateObjectStorage"},
+	{"POST", "/v1/object-storages/{objectStorageId}/resize", "storage", "UpgradeObjectStorage"},
+	{"POST", "/v1/object-storages/{objectStorageId}/cancel", "storage", "CancelObjectStorage"},
+	{"GET", "/v1/object-storages/{objectStorageId}/stats", "storage", "GetObjectStorageStats"},
+	{"GET", "/v1/users/object-storage-credentials/{objectStorageId}", "storage", "GetCredentials"},
+
+	// Private networks
+	{"GET", "/v1/private-networks", "network", "ListPrivateNetworks"},
+	{"POST", "/v1/private-networks", "network", "CreatePrivateNetwork"},
+	{"GET", "/v1/private-networks/{privateNetworkId}", "network", "GetPrivateNetwork"},
+	{"PATCH", "/v1/private-networks/{privateNetworkId}", "network", "UpdatePrivateNetwork"},
+	{"DELETE", "/v1/private-networks/{privateNetworkId}", "network", "DeletePrivateNetwork"},
+	{"POST", "/v1/private-networks/{privateNetworkId}/instances", "network", "AssignInstances"},
+	{"DELETE", "/v1/private-networks/{privateNetworkId}/instances", "network", "UnassignInstances"},
+
+	// DNS
+	{"GET", "/v1/dns/zones", "dns", "ListZones"},
+	{"POST", "/v1/dns/zones", "dns", "CreateZone"},
+	{"GET", "/v1/dns/zones/{zoneName}", "dns", "GetZone"},
+	{"DELETE", "/v1/dns/zones/{zoneName}", "dns", "DeleteZone"},
+	{"GET", "/v1/dns/zones/{zoneName}/records", "dns", "ListRecords"},
+	{"POST", "/v1/dns/zones/{zoneName}/records", "dns", "CreateRecord"},
+	{"GET", "/v1/dns/zones/{zoneName}/records/{recordId}", "dns", "GetRecord"},
+	{"PATCH", "/v1/dns/zones/{zoneName}/records/{recordId}", "dns", "UpdateRecord"},
+	{"DELETE", "/v1/dns/zones/{zoneName}/records/{recordId}", "dns", "DeleteRecord"},
+	{"GET", "/v1/dns/ptrs", "dns", "ListPTRRecords"},
+	{"GET", "/v1/dns/ptrs/{ipAddress}", "dns", "GetPTRRecord"},
+	{"PATCH", "/v1/dns/ptrs/{ipAddress}", "dns", "UpdatePTRRecord"},
+
+	// Secrets
+	{"GET", "/v1/secrets", "secret", "ListSecrets"},
+	{"POST", "/v1/secrets", "secret", "CreateSecret"},
+	{"GET", "/v1/secrets/{secretId}", "secret", "GetSecret"},
+	{"PATCH", "/v1/secrets/{secretId}", "secret", "UpdateSecret"},
+	{"DELETE", "/v1/secrets/{secretId}", "secret", "DeleteSecret"},
+
+	// Tags
+	{"GET", "/v1/tags", "tag", "ListTags"},
+	{"POST", "/v1/tags", "tag", "CreateTag"},
+	{"GET", "/v1/tags/{tagId}", "tag", "GetTag"},
+	{"PATCH", "/v1/tags/{tagId}", "tag", "UpdateTag"},
+	{"DELETE", "/v1/tags/{tagId}", "tag", "DeleteTag"},
+	{"PUT", "/v1/tags/{tagId}/assignments/{resourceType}/{resourceId}", "tag", "AssignTag"},
+	{"DELETE", "/v1/tags/{tagId}/assignments/{resourceType}/{resourceId}", "tag", "UnassignTag"},
+
+	// Users and roles
+	{"GET", "/v1/users", "user", "ListUsers"},
+	{"POST", "/v1/users", "user", "CreateUser"},
+	{"GET", "/v1/users/{userId}", "user", "GetUser"},
+	{"PATCH", "/v1/users/{userId}", "user", "UpdateUser"},
+	{"DELETE", "/v1/users/{userId}", "user", "DeleteUser"},
+	{"GET", "/v1/roles", "user", "ListRoles"},
+	{"POST", "/v1/roles", "user", "CreateRole"},
+	{"GET", "/v1/roles/{roleId}", "user", "GetRole"},
+	{"PATCH", "/v1/roles/{roleId}", "user", "UpdateRole"},
+	{"DELETE", "/v1/roles/{roleId}", "user", "DeleteRole"},
+}
+
+// UnknownPathTemplate is the PathTemplate of requests to endpoints missing
+// from the route table. A fixed value keeps metric labels bounded, as raw
+// paths contain resource IDs.
+const UnknownPathTemplate = "unknown"
+
+// MatchRoute returns the route for a request method and path (which may
+// include a query string). For unknown endpoints the returned route carries
+// UnknownPathTemplate and empty service and operation.
+func MatchRoute(method, path string) Route {
+	if u, err := url.Parse(path); err == nil {
+		path = u.EscapedPath()
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, r := range routes {
+		if r.Method == method && matchTemplate(r.PathTemplate, segments) {
+			return r
+		}
+	}
+
+	return Route{Method: method, PathTemplate: UnknownPathTemplate}
+}
+
+// matchTemplate reports whether path segments match a template, where
+// "{name}" segments match any non-empty segment
+func matchTemplate(template string, segments []string) bool {
+	parts := strings.Split(strings.Trim(template, "/"), "/")
+	if len(parts) != len(segments) {
+		return false
+	}
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package contabo
+
+import "testing"
+
+func TestMatchRoute(t *testing.T) {
+	tests := []struct {
+		method, path  string
+		wantTemplate  string
+		wantOperation string
+	}{
+		{"GET", "/v1/compute/instances", "/v1/compute/instances", "ListInstances"},
+		{"GET", "/v1/compute/instances?page=2&size=10", "/v1/compute/instances", "ListInstances"},
+		{"GET", "/v1/compute/instances/12345", "/v1/compute/instances/{instanceId}", "GetInstance"},
+		{"PATCH", "/v1/compute/instances/12345", "/v1/compute/instances/{instanceId}", "UpdateInstance"},
+		{"POST", "/v1/compute/instances/12345/actions/start", "/v1/compute/instances/{instanceId}/actions/start", "StartInstance"},
+		{"PUT", "/v1/tags/7/assignments/instance/12345", "/v1/tags/{tagId}/assignments/{resourceType}/{resourceId}", "AssignTag"},
+		{"GET", "/v1/dns/zones/example.com/records/9", "/v1/dns/zones/{zoneName}/records/{recordId}", "GetRecord"},
+		// Unknown endpoints never expose the raw path, which holds resource IDs
+		{"DELETE", "/v1/compute/instances/12345", UnknownPathTemplate, ""},
+		{"GET", "/v1/compute/instances/12345/unknown", UnknownPathTemplate, ""},
+		{"GET", "/v1/compute/instances//actions", UnknownPathTemplate, ""},
+		{"GET", "/v2/anything/98765?token=x", UnknownPathTemplate, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			got := MatchRoute(tt.method, tt.path)
+			if got.Method != tt.method || got.PathTemplate != tt.wantTemplate || got.Operation != tt.wantOperation {
+				t.Errorf("MatchRoute() = %+v, want template %q and operation %q", got, tt.wantTemplate, tt.wantOperation)
+			}
+		})
+	}
+}
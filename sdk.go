@@ -22,9 +22,10 @@ type SDK struct {
 	User    *user.Service
 }
 
-// NewSDK creates a new Contabo SDK instance with all services initialized
-func NewSDK(config *Config) (*SDK, error) {
-	client, err := NewClient(config)
+// NewSDK creates a new Contabo SDK instance with all services initialized.
+// Options are passed on to NewClient.
+func NewSDK(config *Config, opts ...Option) (*SDK, error) {
+	client, err := NewClient(config, opts...)
 	if err != nil {
 		return nil, err
 	}
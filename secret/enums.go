@@ -0,0 +1,16 @@
+package secret
+
+// Type is the kind of a secret. Types the SDK does not know are decoded and
+// preserved unchanged; IsValid reports whether a type is known.
+type Type string
+
+// Known secret types
+const (
+	TypeSSH      Type = "ssh"
+	TypePassword Type = "password"
+)
+
+// IsValid reports whether t is a known secret type
+func (t Type) IsValid() bool {
+	return t == TypeSSH || t == TypePassword
+}
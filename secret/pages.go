@@ -0,0 +1,48 @@
+package secret
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllSecrets returns an iterator over the secrets of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllSecrets(ctx context.Context, opts *ListSecretsOptions) iter.Seq2[Secret, error] {
+	return paginate.All(ctx, secretFields.StartPage(opts), s.secretPages(opts), secretKey)
+}
+
+// ListAllSecrets retrieves the secrets of all pages, fetching up to
+// opts.Concurrency pages at once. On error the secrets read so far are
+// returned with it.
+func (s *Service) ListAllSecrets(ctx context.Context, opts *ListSecretsOptions) ([]Secret, error) {
+	return paginate.List(ctx, secretFields.StartPage(opts), secretFields.Concurrency(opts), s.secretPages(opts), secretKey)
+}
+
+// secretPages returns a function fetching a page of secrets
+func (s *Service) secretPages(opts *ListSecretsOptions) paginate.Fetch[Secret] {
+	return func(ctx context.Context, page int) (*paginate.Page[Secret], error) {
+		resp, err := s.ListSecrets(ctx, secretFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Secret]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// secretKey identifies a secret for de-duplication
+func secretKey(s Secret) int64 {
+	return s.SecretID
+}
+
+// secretFields reaches the pagination fields of ListSecretsOptions for internal/paginate
+var secretFields paginate.Fields[ListSecretsOptions] = func(o *ListSecretsOptions) (page, concurrency *int) {
+	return &o.Page, &o.Concurrency
+}
@@ -3,6 +3,9 @@ package secret
 import (
 	"context"
 	"fmt"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +22,24 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListSecretsOptions filters the secrets returned by ListSecrets.
+// Empty fields are not sent.
+type ListSecretsOptions struct {
+	ListOptions
+
+	Name string
+	Type Type
+}
+
+// params returns the filter query parameters
+func (o *ListSecretsOptions) params() map[string]string {
+	return map[string]string{"name": o.Name, "type": string(o.Type)}
 }
 
 // Service handles secret-related API operations
@@ -32,10 +53,10 @@ func NewService(client Client) *Service {
 }
 
 // ListSecrets retrieves a list of secrets
-func (s *Service) ListSecrets(ctx context.Context, opts *ListOptions) (*SecretsResponse, error) {
+func (s *Service) ListSecrets(ctx context.Context, opts *ListSecretsOptions) (*SecretsResponse, error) {
 	path := "/v1/secrets"
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp SecretsResponse
@@ -48,7 +69,10 @@ func (s *Service) ListSecrets(ctx context.Context, opts *ListOptions) (*SecretsR
 
 // GetSecret retrieves a specific secret by ID
 func (s *Service) GetSecret(ctx context.Context, secretID int64) (*Secret, error) {
-	path := fmt.Sprintf("/v1/secrets/%d", secretID)
+	path, err := urlbuild.Path("/v1/secrets/%d", secretID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Secret `json:"data"`
@@ -58,7 +82,7 @@ func (s *Service) GetSecret(ctx context.Context, secretID int64) (*Secret, error
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("secret not found")
+		return nil, fmt.Errorf("secret %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -82,7 +106,10 @@ func (s *Service) CreateSecret(ctx context.Context, req *CreateSecretRequest) (*
 
 // UpdateSecret updates a secret
 func (s *Service) UpdateSecret(ctx context.Context, secretID int64, req *PatchSecretRequest) (*Secret, error) {
-	path := fmt.Sprintf("/v1/secrets/%d", secretID)
+	path, err := urlbuild.Path("/v1/secrets/%d", secretID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Secret `json:"data"`
@@ -100,47 +127,17 @@ func (s *Service) UpdateSecret(ctx context.Context, secretID int64, req *PatchSe
 
 // DeleteSecret deletes a secret
 func (s *Service) DeleteSecret(ctx context.Context, secretID int64) error {
-	path := fmt.Sprintf("/v1/secrets/%d", secretID)
+	path, err := urlbuild.Path("/v1/secrets/%d", secretID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
-	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
@@ -0,0 +1,61 @@
+package secret_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/secret"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListSecretsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *secret.ListSecretsOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"empty options", &secret.ListSecretsOptions{}, ""},
+		{"name", &secret.ListSecretsOptions{Name: "deploy key"}, "name=deploy+key"},
+		{"typed type", &secret.ListSecretsOptions{Type: secret.TypeSSH}, "type=ssh"},
+		{"all filters", &secret.ListSecretsOptions{ListOptions: secret.ListOptions{OrderBy: []string{"name:desc"}}, Name: "root", Type: secret.TypePassword}, "name=root&orderBy=name%3Adesc&type=password"},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.Secret.ListSecrets(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListSecrets() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
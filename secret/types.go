@@ -1,6 +1,9 @@
 package secret
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // Secret represents a stored secret (SSH key or password)
 type Secret struct {
@@ -8,7 +11,7 @@ type Secret struct {
 	TenantID    string    `json:"tenantId"`
 	CustomerID  string    `json:"customerId"`
 	Name        string    `json:"name"`
-	Type        string    `json:"type"`  // "ssh" or "password"
+	Type        Type      `json:"type"`
 	Value       string    `json:"value"`
 	CreatedDate time.Time `json:"createdDate"`
 	UpdatedDate time.Time `json:"updatedDate"`
@@ -35,7 +38,7 @@ type SecretsResponse struct {
 // CreateSecretRequest represents the request body for creating a secret
 type CreateSecretRequest struct {
 	Name  string `json:"name"`
-	Type  string `json:"type"`
+	Type  Type   `json:"type"`
 	Value string `json:"value"`
 }
 
@@ -49,3 +52,22 @@ type PatchSecretRequest struct {
 	Name  *string `json:"name,omitempty"`
 	Value *string `json:"value,omitempty"`
 }
+
+// LogValue implements slog.LogValuer so that the secret value is never logged
+func (s Secret) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("secretId", s.SecretID),
+		slog.String("name", s.Name),
+		slog.String("type", string(s.Type)),
+		slog.String("value", "[REDACTED]"),
+	)
+}
+
+// LogValue implements slog.LogValuer so that the secret value is never logged
+func (r CreateSecretRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", r.Name),
+		slog.String("type", string(r.Type)),
+		slog.String("value", "[REDACTED]"),
+	)
+}
@@ -0,0 +1,18 @@
+package storage
+
+// AutoScalingState tells whether auto-scaling of an object storage is on.
+// States the SDK does not know are decoded and preserved unchanged; IsValid
+// reports whether a state is known.
+type AutoScalingState string
+
+// Known auto-scaling states
+const (
+	AutoScalingEnabled  AutoScalingState = "enabled"
+	AutoScalingDisabled AutoScalingState = "disabled"
+	AutoScalingError    AutoScalingState = "error"
+)
+
+// IsValid reports whether s is a known auto-scaling state
+func (s AutoScalingState) IsValid() bool {
+	return s == AutoScalingEnabled || s == AutoScalingDisabled || s == AutoScalingError
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllObjectStorages returns an iterator over the object storages of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllObjectStorages(ctx context.Context, opts *ListOptions) iter.Seq2[ObjectStorage, error] {
+	return paginate.All(ctx, listFields.StartPage(opts), s.objectStoragePages(opts), objectStorageKey)
+}
+
+// ListAllObjectStorages retrieves the object storages of all pages, fetching up to
+// opts.Concurrency pages at once. On error the object storages read so far are
+// returned with it.
+func (s *Service) ListAllObjectStorages(ctx context.Context, opts *ListOptions) ([]ObjectStorage, error) {
+	return paginate.List(ctx, listFields.StartPage(opts), listFields.Concurrency(opts), s.objectStoragePages(opts), objectStorageKey)
+}
+
+// objectStoragePages returns a function fetching a page of object storages
+func (s *Service) objectStoragePages(opts *ListOptions) paginate.Fetch[ObjectStorage] {
+	return func(ctx context.Context, page int) (*paginate.Page[ObjectStorage], error) {
+		resp, err := s.ListObjectStorages(ctx, listFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[ObjectStorage]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// objectStorageKey identifies an object storage for de-duplication
+func objectStorageKey(o ObjectStorage) string {
+	return o.ObjectStorageID
+}
+
+// listFields reaches the pagination fields of ListOptions for internal/paginate
+var listFields paginate.Fields[ListOptions] = func(o *ListOptions) (page, concurrency *int) {
+	return &o.Page, &o.Concurrency
+}
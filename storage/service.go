@@ -3,6 +3,9 @@ package storage
 import (
 	"context"
 	"fmt"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +22,10 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
 }
 
 // Service handles object storage-related API operations
@@ -48,7 +55,10 @@ func (s *Service) ListObjectStorages(ctx context.Context, opts *ListOptions) (*O
 
 // GetObjectStorage retrieves a specific object storage by ID
 func (s *Service) GetObjectStorage(ctx context.Context, objectStorageID string) (*ObjectStorage, error) {
-	path := fmt.Sprintf("/v1/object-storages/%s", objectStorageID)
+	path, err := urlbuild.Path("/v1/object-storages/%s", objectStorageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []ObjectStorage `json:"data"`
@@ -58,7 +68,7 @@ func (s *Service) GetObjectStorage(ctx context.Context, objectStorageID string)
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("object storage not found")
+		return nil, fmt.Errorf("object storage %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -82,7 +92,10 @@ func (s *Service) CreateObjectStorage(ctx context.Context, req *CreateObjectStor
 
 // UpdateObjectStorage updates an object storage
 func (s *Service) UpdateObjectStorage(ctx context.Context, objectStorageID string, req *PatchObjectStorageRequest) (*ObjectStorage, error) {
-	path := fmt.Sprintf("/v1/object-storages/%s", objectStorageID)
+	path, err := urlbuild.Path("/v1/object-storages/%s", objectStorageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []ObjectStorage `json:"data"`
@@ -100,7 +113,10 @@ func (s *Service) UpdateObjectStorage(ctx context.Context, objectStorageID strin
 
 // UpgradeObjectStorage upgrades object storage capacity
 func (s *Service) UpgradeObjectStorage(ctx context.Context, objectStorageID string, req *UpgradeObjectStorageRequest) (*ObjectStorage, error) {
-	path := fmt.Sprintf("/v1/object-storages/%s/resize", objectStorageID)
+	path, err := urlbuild.Path("/v1/object-storages/%s/resize", objectStorageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []ObjectStorage `json:"data"`
@@ -118,13 +134,19 @@ func (s *Service) UpgradeObjectStorage(ctx context.Context, objectStorageID stri
 
 // CancelObjectStorage cancels an object storage
 func (s *Service) CancelObjectStorage(ctx context.Context, objectStorageID string) error {
-	path := fmt.Sprintf("/v1/object-storages/%s/cancel", objectStorageID)
+	path, err := urlbuild.Path("/v1/object-storages/%s/cancel", objectStorageID)
+	if err != nil {
+		return err
+	}
 	return s.client.Post(ctx, path, nil, nil)
 }
 
 // GetObjectStorageStats retrieves usage statistics for object storage
 func (s *Service) GetObjectStorageStats(ctx context.Context, objectStorageID string) (*ObjectStorageStats, error) {
-	path := fmt.Sprintf("/v1/object-storages/%s/stats", objectStorageID)
+	path, err := urlbuild.Path("/v1/object-storages/%s/stats", objectStorageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp ObjectStorageStatsResponse
 	if err := s.client.Get(ctx, path, &resp); err != nil {
@@ -132,7 +154,7 @@ func (s *Service) GetObjectStorageStats(ctx context.Context, objectStorageID str
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("stats not found")
+		return nil, fmt.Errorf("stats %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -140,7 +162,10 @@ func (s *Service) GetObjectStorageStats(ctx context.Context, objectStorageID str
 
 // GetCredentials retrieves S3 credentials for object storage
 func (s *Service) GetCredentials(ctx context.Context, objectStorageID string) (*Credentials, error) {
-	path := fmt.Sprintf("/v1/users/object-storage-credentials/%s", objectStorageID)
+	path, err := urlbuild.Path("/v1/users/object-storage-credentials/%s", objectStorageID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp CredentialsResponse
 	if err := s.client.Get(ctx, path, &resp); err != nil {
@@ -148,7 +173,7 @@ func (s *Service) GetCredentials(ctx context.Context, objectStorageID string) (*
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("credentials not found")
+		return nil, fmt.Errorf("credentials %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -156,41 +181,8 @@ func (s *Service) GetCredentials(ctx context.Context, objectStorageID string) (*
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
-	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
@@ -1,29 +1,32 @@
 package storage
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // ObjectStorage represents an S3-compatible object storage
 type ObjectStorage struct {
-	TenantID        string    `json:"tenantId"`
-	CustomerID      string    `json:"customerId"`
-	ObjectStorageID string    `json:"objectStorageId"`
-	CreatedDate     time.Time `json:"createdDate"`
-	CancelDate      string    `json:"cancelDate,omitempty"`
-	AutoScaling     AutoScaling `json:"autoScaling"`
-	DataCenter      string    `json:"dataCenter"`
-	TotalPurchasedSpaceTB float64 `json:"totalPurchasedSpaceTB"`
-	S3URL           string    `json:"s3Url"`
-	S3TenantID      string    `json:"s3TenantId"`
-	Status          string    `json:"status"`
-	Region          string    `json:"region"`
-	DisplayName     string    `json:"displayName,omitempty"`
+	TenantID              string      `json:"tenantId"`
+	CustomerID            string      `json:"customerId"`
+	ObjectStorageID       string      `json:"objectStorageId"`
+	CreatedDate           time.Time   `json:"createdDate"`
+	CancelDate            string      `json:"cancelDate,omitempty"`
+	AutoScaling           AutoScaling `json:"autoScaling"`
+	DataCenter            string      `json:"dataCenter"`
+	TotalPurchasedSpaceTB float64     `json:"totalPurchasedSpaceTB"`
+	S3URL                 string      `json:"s3Url"`
+	S3TenantID            string      `json:"s3TenantId"`
+	Status                string      `json:"status"`
+	Region                string      `json:"region"`
+	DisplayName           string      `json:"displayName,omitempty"`
 }
 
 // AutoScaling represents auto-scaling configuration
 type AutoScaling struct {
-	State          string  `json:"state"`
-	SizeLimitTB    float64 `json:"sizeLimitTB"`
-	ErrorMessage   string  `json:"errorMessage,omitempty"`
+	State        AutoScalingState `json:"state"`
+	SizeLimitTB  float64          `json:"sizeLimitTB"`
+	ErrorMessage string           `json:"errorMessage,omitempty"`
 }
 
 // ObjectStoragesResponse represents the response for listing object storages
@@ -46,16 +49,16 @@ type ObjectStoragesResponse struct {
 
 // CreateObjectStorageRequest represents the request body for creating object storage
 type CreateObjectStorageRequest struct {
-	Region      string  `json:"region"`
-	TotalPurchasedSpaceTB float64 `json:"totalPurchasedSpaceTB"`
-	AutoScaling *AutoScalingRequest `json:"autoScaling,omitempty"`
-	DisplayName string  `json:"displayName,omitempty"`
+	Region                string              `json:"region"`
+	TotalPurchasedSpaceTB float64             `json:"totalPurchasedSpaceTB"`
+	AutoScaling           *AutoScalingRequest `json:"autoScaling,omitempty"`
+	DisplayName           string              `json:"displayName,omitempty"`
 }
 
 // AutoScalingRequest represents auto-scaling configuration for creation
 type AutoScalingRequest struct {
-	State       string  `json:"state"`
-	SizeLimitTB float64 `json:"sizeLimitTB"`
+	State       AutoScalingState `json:"state"`
+	SizeLimitTB float64          `json:"sizeLimitTB"`
 }
 
 // CreateObjectStorageResponse represents the response when creating object storage
@@ -71,14 +74,14 @@ type PatchObjectStorageRequest struct {
 
 // UpgradeObjectStorageRequest represents the request body for upgrading object storage
 type UpgradeObjectStorageRequest struct {
-	TotalPurchasedSpaceTB float64 `json:"totalPurchasedSpaceTB"`
+	TotalPurchasedSpaceTB float64             `json:"totalPurchasedSpaceTB"`
 	AutoScaling           *AutoScalingRequest `json:"autoScaling,omitempty"`
 }
 
 // ObjectStorageStats represents usage statistics
 type ObjectStorageStats struct {
-	ObjectStorageID string  `json:"objectStorageId"`
-	UsedSpaceTB     float64 `json:"usedSpaceTB"`
+	ObjectStorageID     string  `json:"objectStorageId"`
+	UsedSpaceTB         float64 `json:"usedSpaceTB"`
 	UsedSpacePercentage float64 `json:"usedSpacePercentage"`
 }
 
@@ -100,3 +103,12 @@ type Credentials struct {
 type CredentialsResponse struct {
 	Data []Credentials `json:"data"`
 }
+
+// LogValue implements slog.LogValuer so that the S3 secret key is never logged
+func (c Credentials) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("accessKey", c.AccessKey),
+		slog.String("secretKey", "[REDACTED]"),
+		slog.String("displayName", c.DisplayName),
+	)
+}
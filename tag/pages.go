@@ -0,0 +1,48 @@
+package tag
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllTags returns an iterator over the tags of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllTags(ctx context.Context, opts *ListTagsOptions) iter.Seq2[Tag, error] {
+	return paginate.All(ctx, tagFields.StartPage(opts), s.tagPages(opts), tagKey)
+}
+
+// ListAllTags retrieves the tags of all pages, fetching up to
+// opts.Concurrency pages at once. On error the tags read so far are
+// returned with it.
+func (s *Service) ListAllTags(ctx context.Context, opts *ListTagsOptions) ([]Tag, error) {
+	return paginate.List(ctx, tagFields.StartPage(opts), tagFields.Concurrency(opts), s.tagPages(opts), tagKey)
+}
+
+// tagPages returns a function fetching a page of tags
+func (s *Service) tagPages(opts *ListTagsOptions) paginate.Fetch[Tag] {
+	return func(ctx context.Context, page int) (*paginate.Page[Tag], error) {
+		resp, err := s.ListTags(ctx, tagFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Tag]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// tagKey identifies a tag for de-duplication
+func tagKey(t Tag) int64 {
+	return t.TagID
+}
+
+// tagFields reaches the pagination fields of ListTagsOptions for internal/paginate
+var tagFields paginate.Fields[ListTagsOptions] = func(o *ListTagsOptions) (page, concurrency *int) {
+	return &o.Page, &o.Concurrency
+}
@@ -3,6 +3,9 @@ package tag
 import (
 	"context"
 	"fmt"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +22,23 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListTagsOptions filters the tags returned by ListTags.
+// Empty fields are not sent.
+type ListTagsOptions struct {
+	ListOptions
+
+	Name string
+}
+
+// params returns the filter query parameters
+func (o *ListTagsOptions) params() map[string]string {
+	return map[string]string{"name": o.Name}
 }
 
 // Service handles tag-related API operations
@@ -32,10 +52,10 @@ func NewService(client Client) *Service {
 }
 
 // ListTags retrieves a list of tags
-func (s *Service) ListTags(ctx context.Context, opts *ListOptions) (*TagsResponse, error) {
+func (s *Service) ListTags(ctx context.Context, opts *ListTagsOptions) (*TagsResponse, error) {
 	path := "/v1/tags"
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp TagsResponse
@@ -48,7 +68,10 @@ func (s *Service) ListTags(ctx context.Context, opts *ListOptions) (*TagsRespons
 
 // GetTag retrieves a specific tag by ID
 func (s *Service) GetTag(ctx context.Context, tagID int64) (*Tag, error) {
-	path := fmt.Sprintf("/v1/tags/%d", tagID)
+	path, err := urlbuild.Path("/v1/tags/%d", tagID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Tag `json:"data"`
@@ -58,7 +81,7 @@ func (s *Service) GetTag(ctx context.Context, tagID int64) (*Tag, error) {
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("tag not found")
+		return nil, fmt.Errorf("tag %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -82,7 +105,10 @@ func (s *Service) CreateTag(ctx context.Context, req *CreateTagRequest) (*Tag, e
 
 // UpdateTag updates a tag
 func (s *Service) UpdateTag(ctx context.Context, tagID int64, req *PatchTagRequest) (*Tag, error) {
-	path := fmt.Sprintf("/v1/tags/%d", tagID)
+	path, err := urlbuild.Path("/v1/tags/%d", tagID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Tag `json:"data"`
@@ -100,59 +126,35 @@ func (s *Service) UpdateTag(ctx context.Context, tagID int64, req *PatchTagReque
 
 // DeleteTag deletes a tag
 func (s *Service) DeleteTag(ctx context.Context, tagID int64) error {
-	path := fmt.Sprintf("/v1/tags/%d", tagID)
+	path, err := urlbuild.Path("/v1/tags/%d", tagID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // AssignTag assigns a tag to a resource
 func (s *Service) AssignTag(ctx context.Context, tagID int64, resourceType string, resourceID string) error {
-	path := fmt.Sprintf("/v1/tags/%d/assignments/%s/%s", tagID, resourceType, resourceID)
+	path, err := urlbuild.Path("/v1/tags/%d/assignments/%s/%s", tagID, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Put(ctx, path, nil, nil)
 }
 
 // UnassignTag unassigns a tag from a resource
 func (s *Service) UnassignTag(ctx context.Context, tagID int64, resourceType string, resourceID string) error {
-	path := fmt.Sprintf("/v1/tags/%d/assignments/%s/%s", tagID, resourceType, resourceID)
+	path, err := urlbuild.Path("/v1/tags/%d/assignments/%s/%s", tagID, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
-	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
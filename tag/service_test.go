@@ -0,0 +1,60 @@
+package tag_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/tag"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListTagsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *tag.ListTagsOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"empty options", &tag.ListTagsOptions{}, ""},
+		{"name", &tag.ListTagsOptions{Name: "prod/eu"}, "name=prod%2Feu"},
+		{"name and size", &tag.ListTagsOptions{ListOptions: tag.ListOptions{Size: 100}, Name: "web"}, "name=web&size=100"},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.Tag.ListTags(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListTags() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package contabo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is an access token used to authorize API requests
+type Token struct {
+	AccessToken string
+	TokenType   string    // Defaults to "Bearer" when empty
+	Expiry      time.Time // Zero means the token never expires
+}
+
+// Type returns the token type used in the Authorization header
+func (t *Token) Type() string {
+	if t.TokenType == "" || strings.EqualFold(t.TokenType, "bearer") {
+		return "Bearer"
+	}
+	return t.TokenType
+}
+
+// Valid reports whether the token is set and not expired
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Before(t.Expiry))
+}
+
+// TokenSource supplies access tokens to the client
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// ContextTokenSource is implemented by token sources that can honour
+// cancellation; the client prefers it over TokenSource when available
+type ContextTokenSource interface {
+	TokenSource
+	TokenContext(ctx context.Context) (*Token, error)
+}
+
+// TokenInvalidator is implemented by token sources that cache tokens. The
+// client calls InvalidateToken when the API rejects a token before its expiry.
+type TokenInvalidator interface {
+	InvalidateToken(accessToken string)
+}
+
+// invalidateToken tells ts that accessToken was rejected, if ts supports it
+func invalidateToken(ts TokenSource, accessToken string) {
+	if inv, ok := ts.(TokenInvalidator); ok {
+		inv.InvalidateToken(accessToken)
+	}
+}
+
+// tokenFromSource obtains a token from ts, passing ctx when ts supports it
+func tokenFromSource(ctx context.Context, ts TokenSource) (*Token, error) {
+	if cts, ok := ts.(ContextTokenSource); ok {
+		return cts.TokenContext(ctx)
+	}
+	return ts.Token()
+}
+
+// staticTokenSource always returns the same token
+type staticTokenSource struct {
+	token *Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns the given
+// bearer token, e.g. a token injected into a CI job
+func StaticTokenSource(accessToken string) TokenSource {
+	return &staticTokenSource{token: &Token{AccessToken: accessToken}}
+}
+
+// Token implements TokenSource
+func (s *staticTokenSource) Token() (*Token, error) {
+	return s.token, nil
+}
+
+// reuseTokenSource caches tokens from another source until they expire
+type reuseTokenSource struct {
+	src    TokenSource
+	leeway time.Duration
+	mu     sync.Mutex
+	token  *Token
+}
+
+// ReuseTokenSource returns a TokenSource that caches the token from src and
+// only asks src for a new one once the cached token is within leeway of expiry
+func ReuseTokenSource(src TokenSource, leeway time.Duration) ContextTokenSource {
+	return &reuseTokenSource{src: src, leeway: leeway}
+}
+
+// Token implements TokenSource
+func (s *reuseTokenSource) Token() (*Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+// TokenContext implements ContextTokenSource
+func (s *reuseTokenSource) TokenContext(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.AccessToken != "" &&
+		(s.token.Expiry.IsZero() || time.Now().Add(s.leeway).Before(s.token.Expiry)) {
+		return s.token, nil
+	}
+
+	token, err := tokenFromSource(ctx, s.src)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// InvalidateToken implements TokenInvalidator
+func (s *reuseTokenSource) InvalidateToken(accessToken string) {
+	s.mu.Lock()
+	if s.token != nil && s.token.AccessToken == accessToken {
+		s.token = nil
+	}
+	s.mu.Unlock()
+
+	invalidateToken(s.src, accessToken)
+}
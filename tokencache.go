@@ -0,0 +1,230 @@
+package contabo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenCache persists encrypted token blobs between processes.
+// AuthManager encrypts entries before calling Store, so implementations only
+// deal with opaque bytes.
+type TokenCache interface {
+	// Load returns the blob stored under key, or nil if there is none
+	Load(key string) ([]byte, error)
+	// Store replaces the blob stored under key
+	Store(key string, data []byte) error
+}
+
+// cachedToken is the plaintext form of a token cache entry
+type cachedToken struct {
+	Token         TokenResponse `json:"token"`
+	TokenExpiry   time.Time     `json:"tokenExpiry"`
+	RefreshExpiry time.Time     `json:"refreshExpiry"`
+}
+
+// tokenCacheKey identifies the cache entry for a client ID, username and auth URL
+func tokenCacheKey(config *Config) string {
+	sum := sha256.Sum256([]byte(config.ClientID + "\x00" + config.Username + "\x00" + config.AuthURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenCacheAEAD derives the AES-GCM cipher used for cache entries from the client secret
+func tokenCacheAEAD(clientSecret string) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, []byte(clientSecret), nil, "contabo token cache v1", 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealCachedToken encrypts entry; the key is bound as additional data so
+// entries cannot be swapped between accounts
+func sealCachedToken(clientSecret, key string, entry *cachedToken) ([]byte, error) {
+	plaintext, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := tokenCacheAEAD(clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(key)), nil
+}
+
+// openCachedToken decrypts a blob produced by sealCachedToken
+func openCachedToken(clientSecret, key string, data []byte) (*cachedToken, error) {
+	aead, err := tokenCacheAEAD(clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("token cache entry is truncated")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token cache entry: %w", err)
+	}
+
+	var entry cachedToken
+	if err := json.Unmarshal(plaintext, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// FileTokenCache stores token cache entries as files in a directory.
+// Files are created with mode 0600 and written atomically; a lock file
+// serialises writers across processes.
+type FileTokenCache struct {
+	dir string
+}
+
+// NewFileTokenCache creates a file token cache in dir, creating it with mode 0700 if needed
+func NewFileTokenCache(dir string) (*FileTokenCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+	return &FileTokenCache{dir: dir}, nil
+}
+
+// DefaultTokenCacheDir returns the per-user cache directory for tokens
+func DefaultTokenCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contabo", "tokens"), nil
+}
+
+// Load implements TokenCache
+func (c *FileTokenCache) Load(key string) ([]byte, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Store implements TokenCache
+func (c *FileTokenCache) Store(key string, data []byte) error {
+	unlock, err := c.lock(key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the file holding the entry for key
+func (c *FileTokenCache) path(key string) string {
+	return filepath.Join(c.dir, key+".token")
+}
+
+const (
+	tokenCacheLockTimeout = 5 * time.Second
+	tokenCacheLockStale   = 30 * time.Second
+)
+
+// lock acquires the cross-process lock file for key. The returned function
+// releases it, unless another writer has since taken it over as stale.
+func (c *FileTokenCache) lock(key string) (func(), error) {
+	lockPath := filepath.Join(c.dir, key+".lock")
+	deadline := time.Now().Add(tokenCacheLockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			// The lock holds a unique owner, as file identity may be reused
+			owner := rand.Text()
+			_, writeErr := f.WriteString(owner)
+			if closeErr := f.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to lock token cache: %w", writeErr)
+			}
+			return func() {
+				if data, err := os.ReadFile(lockPath); err == nil && string(data) == owner {
+					os.Remove(lockPath)
+				}
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock token cache: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > tokenCacheLockStale {
+			takeOverStaleLock(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for token cache lock")
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// takeOverStaleLock moves aside a lock left behind by a crashed process.
+// Renaming to a unique name is atomic, so of several writers finding the same
+// stale lock only one moves it; the others then compete to create a new one.
+// A fresh lock created in the meantime, and moved by mistake, is put back.
+func takeOverStaleLock(lockPath string) {
+	claimed := lockPath + ".stale." + rand.Text()
+	if err := os.Rename(lockPath, claimed); err != nil {
+		return
+	}
+	if info, err := os.Stat(claimed); err == nil && time.Since(info.ModTime()) <= tokenCacheLockStale {
+		// Link fails rather than replace a lock created since
+		os.Link(claimed, lockPath)
+	}
+	os.Remove(claimed)
+}
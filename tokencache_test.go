@@ -0,0 +1,298 @@
+package contabo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSealOpenCachedToken(t *testing.T) {
+	entry := &cachedToken{
+		Token:         TokenResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 300},
+		TokenExpiry:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		RefreshExpiry: time.Date(2026, 1, 2, 4, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		key     string
+		tamper  func([]byte) []byte
+		wantErr bool
+	}{
+		{"round trip", "secret", "key", nil, false},
+		{"wrong secret", "other", "key", nil, true},
+		{"entry of another account", "secret", "other-key", nil, true},
+		{"truncated", "secret", "key", func(b []byte) []byte { return b[:5] }, true},
+		{"empty", "secret", "key", func([]byte) []byte { return nil }, true},
+		{"flipped bit", "secret", "key", func(b []byte) []byte { b[len(b)-1] ^= 1; return b }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := sealCachedToken("secret", "key", entry)
+			if err != nil {
+				t.Fatalf("sealCachedToken() error = %v", err)
+			}
+			if bytes.Contains(data, []byte("access")) {
+				t.Fatal("sealed entry contains the plaintext access token")
+			}
+			if tt.tamper != nil {
+				data = tt.tamper(data)
+			}
+
+			got, err := openCachedToken(tt.secret, tt.key, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openCachedToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Token != entry.Token || !got.TokenExpiry.Equal(entry.TokenExpiry) || !got.RefreshExpiry.Equal(entry.RefreshExpiry)) {
+				t.Errorf("openCachedToken() = %+v, want %+v", got, entry)
+			}
+		})
+	}
+}
+
+func TestSealCachedTokenUsesFreshNonce(t *testing.T) {
+	entry := &cachedToken{Token: TokenResponse{AccessToken: "access"}}
+	a, _ := sealCachedToken("secret", "key", entry)
+	b, _ := sealCachedToken("secret", "key", entry)
+	if bytes.Equal(a, b) {
+		t.Error("sealing the same entry twice gave identical blobs")
+	}
+}
+
+func TestFileTokenCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	cache, err := NewFileTokenCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileTokenCache() error = %v", err)
+	}
+
+	if data, err := cache.Load("missing"); data != nil || err != nil {
+		t.Errorf("Load(missing) = %q, %v, want nil, nil", data, err)
+	}
+
+	for _, data := range [][]byte{[]byte("first"), []byte("second")} {
+		if err := cache.Store("key", data); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+		if got, err := cache.Load("key"); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("Load() = %q, %v, want %q", got, err, data)
+		}
+	}
+
+	info, err := os.Stat(cache.path("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("entry mode = %v, want 0600", perm)
+	}
+	if info, err := os.Stat(dir); err != nil {
+		t.Fatal(err)
+	} else if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("directory mode = %v, want 0700", perm)
+	}
+
+	// Neither temporary files nor the lock file are left behind
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("directory holds %d files, want only the entry", len(files))
+	}
+}
+
+func TestFileTokenCacheLock(t *testing.T) {
+	tests := []struct {
+		name     string
+		lockAge  time.Duration // Age of a lock file left behind by another process
+		wantWait bool
+	}{
+		{"held lock", 0, true},
+		{"stale lock", 2 * tokenCacheLockStale, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := NewFileTokenCache(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			lockPath := filepath.Join(cache.dir, "key.lock")
+			if err := os.WriteFile(lockPath, nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			modTime := time.Now().Add(-tt.lockAge)
+			if err := os.Chtimes(lockPath, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+
+			stored := make(chan error, 1)
+			go func() { stored <- cache.Store("key", []byte("data")) }()
+
+			select {
+			case err := <-stored:
+				if tt.wantWait {
+					t.Fatalf("Store() = %v while the lock was held", err)
+				}
+				if err != nil {
+					t.Fatalf("Store() error = %v", err)
+				}
+				return
+			case <-time.After(100 * time.Millisecond):
+				if !tt.wantWait {
+					t.Fatal("Store() still waiting on a stale lock")
+				}
+			}
+
+			os.Remove(lockPath)
+			if err := <-stored; err != nil {
+				t.Fatalf("Store() error = %v after the lock was released", err)
+			}
+		})
+	}
+}
+
+func TestFileTokenCacheStaleLockTakeover(t *testing.T) {
+	cache, err := NewFileTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(cache.dir, "key.lock")
+
+	for round := range 10 {
+		if err := os.WriteFile(lockPath, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		stale := time.Now().Add(-2 * tokenCacheLockStale)
+		if err := os.Chtimes(lockPath, stale, stale); err != nil {
+			t.Fatal(err)
+		}
+
+		// Several writers find the same stale lock at once; only one may hold it at a time
+		var (
+			wg      sync.WaitGroup
+			holders atomic.Int32
+			start   = make(chan struct{})
+		)
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				unlock, err := cache.lock("key")
+				if err != nil {
+					t.Errorf("round %d: lock() error = %v", round, err)
+					return
+				}
+				if n := holders.Add(1); n != 1 {
+					t.Errorf("round %d: %d writers hold the lock", round, n)
+				}
+				time.Sleep(5 * time.Millisecond)
+				holders.Add(-1)
+				unlock()
+			}()
+		}
+		close(start)
+		wg.Wait()
+		if t.Failed() {
+			return
+		}
+	}
+
+	// Neither the lock nor moved-aside stale locks are left behind
+	files, _ := os.ReadDir(cache.dir)
+	for _, f := range files {
+		if strings.Contains(f.Name(), ".lock") {
+			t.Errorf("lock file %s left behind", f.Name())
+		}
+	}
+}
+
+func TestTakeOverStaleLockKeepsFreshLock(t *testing.T) {
+	cache, err := NewFileTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(cache.dir, "key.lock")
+
+	unlock, err := cache.lock("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, _ := os.ReadFile(lockPath)
+
+	// A writer that saw the previous lock as stale acts only after the lock
+	// was taken over and created anew
+	takeOverStaleLock(lockPath)
+
+	if data, err := os.ReadFile(lockPath); err != nil || !bytes.Equal(data, owner) {
+		t.Fatalf("lock after a late takeover = %q, %v, want the fresh lock kept", data, err)
+	}
+	unlock()
+	if files, _ := os.ReadDir(cache.dir); len(files) != 0 {
+		t.Errorf("directory holds %d files after unlock, want none", len(files))
+	}
+}
+
+func TestFileTokenCacheUnlockAfterTakeover(t *testing.T) {
+	cache, err := NewFileTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(cache.dir, "key.lock")
+
+	// A slow writer whose lock went stale must not release the lock of the
+	// writer that took it over
+	unlockSlow, err := cache.lock("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * tokenCacheLockStale)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := cache.lock("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unlockSlow()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Errorf("lock removed by its previous holder: %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock not released: %v", err)
+	}
+}
+
+func TestFileTokenCacheConcurrentStore(t *testing.T) {
+	cache, err := NewFileTokenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.Store("key", []byte(fmt.Sprintf("entry-%d", i))); err != nil {
+				t.Errorf("Store() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Writes are atomic: the entry is one of the complete values
+	data, err := cache.Load("key")
+	if err != nil || !bytes.HasPrefix(data, []byte("entry-")) || len(data) > len("entry-9") {
+		t.Errorf("Load() = %q, %v, want one complete entry", data, err)
+	}
+}
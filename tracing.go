@@ -0,0 +1,89 @@
+package contabo
+
+import (
+	"context"
+	"net/http"
+)
+
+// SpanInfo describes the API call a span is started for
+type SpanInfo struct {
+	Service      string
+	Operation    string
+	Method       string
+	PathTemplate string
+}
+
+// SpanResult describes how an API call ended
+type SpanResult struct {
+	StatusCode int    // HTTP status, 0 if no response was received
+	RequestID  string // x-request-id sent with the call
+	TraceID    string // x-trace-id sent with the call, if any
+	Err        error
+}
+
+// Span is a single traced API call
+type Span interface {
+	// TraceContext returns the W3C trace context to propagate to the API
+	TraceContext() TraceContext
+	// End finishes the span
+	End(result SpanResult)
+}
+
+// Tracer creates a span per API call. Adapters for tracing libraries such as
+// OpenTelemetry implement this interface outside the SDK.
+type Tracer interface {
+	Start(ctx context.Context, info SpanInfo) (context.Context, Span)
+}
+
+// noopTracer is the default Tracer; it only forwards an existing trace context
+type noopTracer struct{}
+
+// noopSpan propagates the trace context found in the caller's context
+type noopSpan struct {
+	tc TraceContext
+}
+
+// Start implements Tracer
+func (noopTracer) Start(ctx context.Context, info SpanInfo) (context.Context, Span) {
+	tc, _ := TraceContextFromContext(ctx)
+	return ctx, noopSpan{tc: tc}
+}
+
+// TraceContext implements Span
+func (s noopSpan) TraceContext() TraceContext {
+	return s.tc
+}
+
+// End implements Span
+func (noopSpan) End(SpanResult) {}
+
+// trace wraps an API call in a span and propagates its trace context
+func (c *Client) trace(ctx context.Context, call *Call, next Handler) (*http.Response, error) {
+	ctx, span := c.tracer.Start(ctx, SpanInfo{
+		Service:      call.Service,
+		Operation:    call.Operation,
+		Method:       call.Method,
+		PathTemplate: call.PathTemplate,
+	})
+
+	tc := span.TraceContext()
+	if tc.TraceParent != "" {
+		call.Header.Set("traceparent", tc.TraceParent)
+		if tc.TraceState != "" {
+			call.Header.Set("tracestate", tc.TraceState)
+		}
+		// Group the call in Contabo's logs by the distributed trace unless set explicitly
+		if _, ok := TraceIDFromContext(ctx); !ok && tc.TraceID() != "" {
+			ctx = WithTraceID(ctx, tc.TraceID())
+		}
+	}
+
+	resp, err := next(ctx, call)
+
+	result := SpanResult{StatusCode: callStatusCode(resp, err), Err: err}
+	result.RequestID, _ = RequestIDFromContext(ctx)
+	result.TraceID, _ = TraceIDFromContext(ctx)
+	span.End(result)
+
+	return resp, err
+}
@@ -0,0 +1,16 @@
+package user
+
+// RoleType is the kind of permissions a role grants. Types the SDK does not
+// know are decoded and preserved unchanged; IsValid reports whether a type is known.
+type RoleType string
+
+// Known role types
+const (
+	RoleTypeAPIPermission      RoleType = "apiPermission"
+	RoleTypeResourcePermission RoleType = "resourcePermission"
+)
+
+// IsValid reports whether t is a known role type
+func (t RoleType) IsValid() bool {
+	return t == RoleTypeAPIPermission || t == RoleTypeResourcePermission
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"context"
+	"iter"
+
+	"github.com/mithucste30/contabo-api-golang/internal/paginate"
+)
+
+// AllUsers returns an iterator over the users of all pages, starting
+// at opts.Page. Size and OrderBy of opts are kept for every page.
+func (s *Service) AllUsers(ctx context.Context, opts *ListUsersOptions) iter.Seq2[User, error] {
+	return paginate.All(ctx, userFields.StartPage(opts), s.userPages(opts), userKey)
+}
+
+// ListAllUsers retrieves the users of all pages, fetching up to
+// opts.Concurrency pages at once. On error the users read so far are
+// returned with it.
+func (s *Service) ListAllUsers(ctx context.Context, opts *ListUsersOptions) ([]User, error) {
+	return paginate.List(ctx, userFields.StartPage(opts), userFields.Concurrency(opts), s.userPages(opts), userKey)
+}
+
+// userPages returns a function fetching a page of users
+func (s *Service) userPages(opts *ListUsersOptions) paginate.Fetch[User] {
+	return func(ctx context.Context, page int) (*paginate.Page[User], error) {
+		resp, err := s.ListUsers(ctx, userFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[User]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// userKey identifies a user for de-duplication
+func userKey(u User) string {
+	return u.UserID
+}
+
+// AllRoles returns an iterator over the roles of all pages
+func (s *Service) AllRoles(ctx context.Context, opts *ListOptions) iter.Seq2[Role, error] {
+	return paginate.All(ctx, listFields.StartPage(opts), s.rolePages(opts), roleKey)
+}
+
+// ListAllRoles retrieves the roles of all pages
+func (s *Service) ListAllRoles(ctx context.Context, opts *ListOptions) ([]Role, error) {
+	return paginate.List(ctx, listFields.StartPage(opts), listFields.Concurrency(opts), s.rolePages(opts), roleKey)
+}
+
+// rolePages returns a function fetching a page of roles
+func (s *Service) rolePages(opts *ListOptions) paginate.Fetch[Role] {
+	return func(ctx context.Context, page int) (*paginate.Page[Role], error) {
+		resp, err := s.ListRoles(ctx, listFields.ForPage(opts, page))
+		if err != nil {
+			return nil, err
+		}
+		return &paginate.Page[Role]{
+			Items:         resp.Data,
+			Size:          resp.Pagination.Size,
+			TotalElements: resp.Pagination.TotalElements,
+			TotalPages:    resp.Pagination.TotalPages,
+			Next:          resp.Links.Next,
+		}, nil
+	}
+}
+
+// roleKey identifies a role for de-duplication
+func roleKey(r Role) int64 {
+	return r.RoleID
+}
+
+// Pagination fields of the list options, for internal/paginate
+var (
+	listFields paginate.Fields[ListOptions]      = func(o *ListOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+	userFields paginate.Fields[ListUsersOptions] = func(o *ListUsersOptions) (page, concurrency *int) { return &o.Page, &o.Concurrency }
+)
@@ -3,6 +3,10 @@ package user
 import (
 	"context"
 	"fmt"
+	"strconv"
+
+	"github.com/mithucste30/contabo-api-golang/internal/apierr"
+	"github.com/mithucste30/contabo-api-golang/internal/urlbuild"
 )
 
 // Client interface for making API requests
@@ -19,6 +23,28 @@ type ListOptions struct {
 	Page    int
 	Size    int
 	OrderBy []string
+
+	// Number of pages ListAll methods fetch concurrently once the first page
+	// reports the total (defaults to 1, fetching page by page)
+	Concurrency int
+}
+
+// ListUsersOptions filters the users returned by ListUsers.
+// Empty fields are not sent.
+type ListUsersOptions struct {
+	ListOptions
+
+	Email   string
+	Enabled *bool
+}
+
+// params returns the filter query parameters
+func (o *ListUsersOptions) params() map[string]string {
+	params := map[string]string{"email": o.Email}
+	if o.Enabled != nil {
+		params["enabled"] = strconv.FormatBool(*o.Enabled)
+	}
+	return params
 }
 
 // Service handles user-related API operations
@@ -34,10 +60,10 @@ func NewService(client Client) *Service {
 // Users
 
 // ListUsers retrieves a list of users
-func (s *Service) ListUsers(ctx context.Context, opts *ListOptions) (*UsersResponse, error) {
+func (s *Service) ListUsers(ctx context.Context, opts *ListUsersOptions) (*UsersResponse, error) {
 	path := "/v1/users"
 	if opts != nil {
-		path += buildQueryString(opts, nil)
+		path += buildQueryString(&opts.ListOptions, opts.params())
 	}
 
 	var resp UsersResponse
@@ -50,7 +76,10 @@ func (s *Service) ListUsers(ctx context.Context, opts *ListOptions) (*UsersRespo
 
 // GetUser retrieves a specific user by ID
 func (s *Service) GetUser(ctx context.Context, userID string) (*User, error) {
-	path := fmt.Sprintf("/v1/users/%s", userID)
+	path, err := urlbuild.Path("/v1/users/%s", userID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []User `json:"data"`
@@ -60,7 +89,7 @@ func (s *Service) GetUser(ctx context.Context, userID string) (*User, error) {
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("user not found")
+		return nil, fmt.Errorf("user %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -84,7 +113,10 @@ func (s *Service) CreateUser(ctx context.Context, req *CreateUserRequest) (*User
 
 // UpdateUser updates a user
 func (s *Service) UpdateUser(ctx context.Context, userID string, req *PatchUserRequest) (*User, error) {
-	path := fmt.Sprintf("/v1/users/%s", userID)
+	path, err := urlbuild.Path("/v1/users/%s", userID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []User `json:"data"`
@@ -102,7 +134,10 @@ func (s *Service) UpdateUser(ctx context.Context, userID string, req *PatchUserR
 
 // DeleteUser deletes a user
 func (s *Service) DeleteUser(ctx context.Context, userID string) error {
-	path := fmt.Sprintf("/v1/users/%s", userID)
+	path, err := urlbuild.Path("/v1/users/%s", userID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
@@ -125,7 +160,10 @@ func (s *Service) ListRoles(ctx context.Context, opts *ListOptions) (*RolesRespo
 
 // GetRole retrieves a specific role by ID
 func (s *Service) GetRole(ctx context.Context, roleID int64) (*Role, error) {
-	path := fmt.Sprintf("/v1/roles/%d", roleID)
+	path, err := urlbuild.Path("/v1/roles/%d", roleID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Role `json:"data"`
@@ -135,7 +173,7 @@ func (s *Service) GetRole(ctx context.Context, roleID int64) (*Role, error) {
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("role not found")
+		return nil, fmt.Errorf("role %w", apierr.ErrNotFound)
 	}
 
 	return &resp.Data[0], nil
@@ -159,7 +197,10 @@ func (s *Service) CreateRole(ctx context.Context, req *CreateRoleRequest) (*Role
 
 // UpdateRole updates a role
 func (s *Service) UpdateRole(ctx context.Context, roleID int64, req *PatchRoleRequest) (*Role, error) {
-	path := fmt.Sprintf("/v1/roles/%d", roleID)
+	path, err := urlbuild.Path("/v1/roles/%d", roleID)
+	if err != nil {
+		return nil, err
+	}
 
 	var resp struct {
 		Data []Role `json:"data"`
@@ -177,47 +218,17 @@ func (s *Service) UpdateRole(ctx context.Context, roleID int64, req *PatchRoleRe
 
 // DeleteRole deletes a role
 func (s *Service) DeleteRole(ctx context.Context, roleID int64) error {
-	path := fmt.Sprintf("/v1/roles/%d", roleID)
+	path, err := urlbuild.Path("/v1/roles/%d", roleID)
+	if err != nil {
+		return err
+	}
 	return s.client.Delete(ctx, path)
 }
 
 // buildQueryString builds a query string from ListOptions and additional parameters
 func buildQueryString(opts *ListOptions, params map[string]string) string {
-	values := make(map[string][]string)
-
-	if opts != nil {
-		if opts.Page > 0 {
-			values["page"] = []string{fmt.Sprintf("%d", opts.Page)}
-		}
-		if opts.Size > 0 {
-			values["size"] = []string{fmt.Sprintf("%d", opts.Size)}
-		}
-		if len(opts.OrderBy) > 0 {
-			values["orderBy"] = opts.OrderBy
-		}
+	if opts == nil {
+		return urlbuild.Query(0, 0, nil, params)
 	}
-
-	for k, v := range params {
-		if v != "" {
-			values[k] = []string{v}
-		}
-	}
-
-	if len(values) == 0 {
-		return ""
-	}
-
-	query := "?"
-	first := true
-	for k, vlist := range values {
-		for _, v := range vlist {
-			if !first {
-				query += "&"
-			}
-			query += k + "=" + v
-			first = false
-		}
-	}
-
-	return query
+	return urlbuild.Query(opts.Page, opts.Size, opts.OrderBy, params)
 }
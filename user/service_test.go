@@ -0,0 +1,63 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	contabo "github.com/mithucste30/contabo-api-golang"
+	"github.com/mithucste30/contabo-api-golang/contabotest"
+	"github.com/mithucste30/contabo-api-golang/user"
+)
+
+// queryRecorder records the query string of the last GET request sent through it
+type queryRecorder struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		r.mu.Lock()
+		r.last = req.URL.RawQuery
+		r.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *queryRecorder) query() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestListUsersQuery(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name string
+		opts *user.ListUsersOptions
+		want string
+	}{
+		{"nil options", nil, ""},
+		{"enabled unset", &user.ListUsersOptions{}, ""},
+		{"enabled", &user.ListUsersOptions{Enabled: &enabled}, "enabled=true"},
+		{"disabled", &user.ListUsersOptions{Enabled: &disabled}, "enabled=false"},
+		{"email", &user.ListUsersOptions{Email: "ops+api@example.com"}, "email=ops%2Bapi%40example.com"},
+	}
+
+	recorder := &queryRecorder{}
+	sdk, _ := contabotest.NewSDK(t, contabo.WithTransport(recorder))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sdk.User.ListUsers(context.Background(), tt.opts); err != nil {
+				t.Fatalf("ListUsers() error = %v", err)
+			}
+			if got := recorder.query(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,32 +4,32 @@ import "time"
 
 // User represents a user with API access
 type User struct {
-	UserID      string    `json:"userId"`
-	TenantID    string    `json:"tenantId"`
-	CustomerID  string    `json:"customerId"`
-	FirstName   string    `json:"firstName"`
-	LastName    string    `json:"lastName"`
-	Email       string    `json:"email"`
-	EmailVerified bool    `json:"emailVerified"`
-	Enabled     bool      `json:"enabled"`
-	TOTP        bool      `json:"totp"`
-	Admin       bool      `json:"admin"`
-	Roles       []Role    `json:"roles"`
-	CreatedDate time.Time `json:"createdDate"`
-	UpdatedDate time.Time `json:"updatedDate"`
+	UserID        string    `json:"userId"`
+	TenantID      string    `json:"tenantId"`
+	CustomerID    string    `json:"customerId"`
+	FirstName     string    `json:"firstName"`
+	LastName      string    `json:"lastName"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Enabled       bool      `json:"enabled"`
+	TOTP          bool      `json:"totp"`
+	Admin         bool      `json:"admin"`
+	Roles         []Role    `json:"roles"`
+	CreatedDate   time.Time `json:"createdDate"`
+	UpdatedDate   time.Time `json:"updatedDate"`
 }
 
 // Role represents a user role with permissions
 type Role struct {
-	RoleID      int64     `json:"roleId"`
-	TenantID    string    `json:"tenantId"`
-	CustomerID  string    `json:"customerId"`
-	Name        string    `json:"name"`
-	Admin       bool      `json:"admin"`
-	AccessAllResources bool `json:"accessAllResources"`
-	Type        string    `json:"type"`
-	CreatedDate time.Time `json:"createdDate"`
-	UpdatedDate time.Time `json:"updatedDate"`
+	RoleID             int64     `json:"roleId"`
+	TenantID           string    `json:"tenantId"`
+	CustomerID         string    `json:"customerId"`
+	Name               string    `json:"name"`
+	Admin              bool      `json:"admin"`
+	AccessAllResources bool      `json:"accessAllResources"`
+	Type               RoleType  `json:"type"`
+	CreatedDate        time.Time `json:"createdDate"`
+	UpdatedDate        time.Time `json:"updatedDate"`
 }
 
 // UsersResponse represents the response for listing users
@@ -67,12 +67,12 @@ type CreateUserResponse struct {
 
 // PatchUserRequest represents the request body for updating a user
 type PatchUserRequest struct {
-	FirstName *string  `json:"firstName,omitempty"`
-	LastName  *string  `json:"lastName,omitempty"`
-	Email     *string  `json:"email,omitempty"`
-	Enabled   *bool    `json:"enabled,omitempty"`
-	Admin     *bool    `json:"admin,omitempty"`
-	Roles     []int64  `json:"roles,omitempty"`
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+	Email     *string `json:"email,omitempty"`
+	Enabled   *bool   `json:"enabled,omitempty"`
+	Admin     *bool   `json:"admin,omitempty"`
+	Roles     []int64 `json:"roles,omitempty"`
 }
 
 // RolesResponse represents the response for listing roles
@@ -98,7 +98,7 @@ type CreateRoleRequest struct {
 	Name               string            `json:"name"`
 	Admin              bool              `json:"admin"`
 	AccessAllResources bool              `json:"accessAllResources"`
-	Type               string            `json:"type"` // "apiPermission" or "resourcePermission"
+	Type               RoleType          `json:"type"`
 	Permissions        map[string]string `json:"permissions,omitempty"`
 	TagIDs             []int64           `json:"tagIds,omitempty"`
 }